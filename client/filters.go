@@ -10,6 +10,8 @@ import (
 )
 
 func (r Roles) AddFilter(ctx context.Context, sender, filterName, filterDescription string) string {
+	ctx = r.WithSender(ctx, sender)
+
 	if len(filterDescription) > 0 && filterDescription[0] == '"' {
 		filterDescription = filterDescription[1:]
 	}
@@ -56,6 +58,8 @@ func (r Roles) ListFilters(ctx context.Context) string {
 }
 
 func (r Roles) RemoveFilter(ctx context.Context, sender, name string) string {
+	ctx = r.WithSender(ctx, sender)
+
 	canPerform, err := r.Permissions.CanPerform(ctx, sender)
 	if err != nil {
 		return common.SendFatal(err.Error())
@@ -99,6 +103,8 @@ func (r Roles) ListMembers(ctx context.Context, name string) string {
 }
 
 func (r Roles) RemoveAllMembers(ctx context.Context, name, sender string) error {
+	ctx = r.WithSender(ctx, sender)
+
 	members, err := r.RoleClient.GetMembers(ctx, &rolesrv.Filter{Name: name})
 	if err != nil {
 		return err
@@ -109,7 +115,7 @@ func (r Roles) RemoveAllMembers(ctx context.Context, name, sender string) error
 		return err
 	}
 
-	_, err = r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, false))
+	_, err = r.RoleClient.SyncToChatService(ctx, r.GetUserSyncRequest(sender, members.Members))
 	if err != nil {
 		return err
 	}
@@ -118,22 +124,17 @@ func (r Roles) RemoveAllMembers(ctx context.Context, name, sender string) error
 }
 
 func (r Roles) AddMember(ctx context.Context, sender, user, filter string) string {
-	canPerform, err := r.Permissions.CanPerform(ctx, sender)
-	if err != nil {
-		return common.SendFatal(err.Error())
-	}
-
-	if !canPerform {
-		return common.SendError("User doesn't have permission to this command")
-	}
+	ctx = r.WithSender(ctx, sender)
 
-	_, err = r.RoleClient.AddMembers(ctx,
+	// role-srv checks permissions itself, which lets a role's managers
+	// through as well as admins
+	_, err := r.RoleClient.AddMembers(ctx,
 		&rolesrv.Members{Name: []string{user}, Filter: filter})
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
 
-	_, err = r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, false))
+	_, err = r.RoleClient.SyncToChatService(ctx, r.GetUserSyncRequest(sender, []string{user}))
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
@@ -141,23 +142,40 @@ func (r Roles) AddMember(ctx context.Context, sender, user, filter string) strin
 	return common.SendSuccess(fmt.Sprintf("Added '%s' to '%s'\n", user, filter))
 }
 
-func (r Roles) RemoveMember(ctx context.Context, sender, user, filter string) string {
-	canPerform, err := r.Permissions.CanPerform(ctx, sender)
+// AddTemporaryMember adds user to filter until duration has passed, after
+// which role-srv takes them out again on its own.
+func (r Roles) AddTemporaryMember(ctx context.Context, sender, user, filter string, duration time.Duration) string {
+	ctx = r.WithSender(ctx, sender)
+
+	// role-srv checks permissions itself, which lets a role's managers
+	// through as well as admins
+	expiresAt := time.Now().Add(duration)
+	_, err := r.RoleClient.AddMembers(ctx,
+		&rolesrv.Members{Name: []string{user}, Filter: filter, ExpiresAt: []int64{expiresAt.Unix()}})
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
 
-	if !canPerform {
-		return common.SendError("User doesn't have permission to this command")
+	_, err = r.RoleClient.SyncToChatService(ctx, r.GetUserSyncRequest(sender, []string{user}))
+	if err != nil {
+		return common.SendFatal(err.Error())
 	}
 
-	_, err = r.RoleClient.RemoveMembers(ctx,
+	return common.SendSuccess(fmt.Sprintf("Added '%s' to '%s' until %s\n", user, filter, expiresAt.UTC().Format(time.RFC1123)))
+}
+
+func (r Roles) RemoveMember(ctx context.Context, sender, user, filter string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	// role-srv checks permissions itself, which lets a role's managers
+	// through as well as admins
+	_, err := r.RoleClient.RemoveMembers(ctx,
 		&rolesrv.Members{Name: []string{user}, Filter: filter})
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
 
-	_, err = r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, false))
+	_, err = r.RoleClient.SyncToChatService(ctx, r.GetUserSyncRequest(sender, []string{user}))
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
@@ -167,6 +185,8 @@ func (r Roles) RemoveMember(ctx context.Context, sender, user, filter string) st
 
 
 func (r Roles) SyncMembers(ctx context.Context, sender string) string {
+	ctx = r.WithSender(ctx, sender)
+
 	//var buffer bytes.Buffer
 	_, err := r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, true))
 
@@ -6,13 +6,40 @@ import (
 	"fmt"
 	"context"
 	"bytes"
+	"github.com/micro/go-micro/metadata"
+	common "github.com/chremoas/services-common/command"
+	"time"
 )
 
+// WithSender tells role-srv who a call is on behalf of, it checks their
+// permissions itself.
+func (r Roles) WithSender(ctx context.Context, sender string) context.Context {
+	s := strings.Split(sender, ":")
+
+	md, ok := metadata.FromContext(ctx)
+	if ok {
+		md = metadata.Copy(md)
+	} else {
+		md = metadata.Metadata{}
+	}
+	md[rolesrv.UserMetadataKey] = s[1]
+
+	return metadata.NewContext(ctx, md)
+}
+
 func (r Roles) GetSyncRequest(sender string, sendMessage bool) *rolesrv.SyncRequest {
 	s := strings.Split(sender, ":")
 	return &rolesrv.SyncRequest{ChannelId: s[0], UserId: s[1], SendMessage: sendMessage}
 }
 
+// GetUserSyncRequest is a sync of just the given users, for after changing
+// their filters.
+func (r Roles) GetUserSyncRequest(sender string, users []string) *rolesrv.SyncRequest {
+	request := r.GetSyncRequest(sender, false)
+	request.UserIds = users
+	return request
+}
+
 func (r Roles) MapName(ctx context.Context, members []string) (buffer bytes.Buffer, names []string, err error) {
 	users, err := r.RoleClient.GetDiscordUserList(ctx, &rolesrv.NilMessage{})
 	var found = false
@@ -41,4 +68,34 @@ func (r Roles) MapName(ctx context.Context, members []string) (buffer bytes.Buff
 	}
 
 	return buffer, names, err
-}
\ No newline at end of file
+}
+
+// AuditLog lists the most recent changes, optionally only those by actor or
+// to target.
+func (r Roles) AuditLog(ctx context.Context, sender, actor, target string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	var buffer bytes.Buffer
+
+	log, err := r.RoleClient.GetAuditLog(ctx, &rolesrv.AuditQuery{Actor: actor, Target: target, Limit: 20})
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	for _, entry := range log.Entries {
+		buffer.WriteString(fmt.Sprintf("%s %s %s by %s\n",
+			time.Unix(entry.Time, 0).UTC().Format(time.RFC3339), entry.Method, entry.Target, entry.Actor))
+		if entry.Before != "" {
+			buffer.WriteString(fmt.Sprintf("\t- %s\n", entry.Before))
+		}
+		if entry.After != "" {
+			buffer.WriteString(fmt.Sprintf("\t+ %s\n", entry.After))
+		}
+	}
+
+	if buffer.Len() == 0 {
+		return common.SendSuccess("Nothing in the audit log\n")
+	}
+
+	return fmt.Sprintf("```%s```", buffer.String())
+}
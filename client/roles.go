@@ -11,6 +11,7 @@ import (
 	"github.com/chremoas/services-common/sets"
 	"go.uber.org/zap"
 	"strconv"
+	"strings"
 )
 
 type Roles struct {
@@ -33,7 +34,7 @@ func (r Roles) ListRoles(ctx context.Context, all, sig bool) string {
 
 	for role := range roles.Roles {
 		if roles.Roles[role].Sig == sig {
-			if roles.Roles[role].Sig && !roles.Roles[role].Joinable && !all {
+			if roles.Roles[role].Sig && !roles.Roles[role].Joinable && !roles.Roles[role].JoinApproval && !all {
 				continue
 			}
 			roleList[roles.Roles[role].ShortName] = roles.Roles[role].Name
@@ -57,6 +58,8 @@ func (r Roles) ListRoles(ctx context.Context, all, sig bool) string {
 }
 
 func (r Roles) AddRole(ctx context.Context, sender, shortName, roleType, filterA, filterB string, joinable bool, roleName string, sig bool) string {
+	ctx = r.WithSender(ctx, sender)
+
 	if len(roleName) > 0 && roleName[0] == '"' {
 		roleName = roleName[1:]
 	}
@@ -98,6 +101,8 @@ func (r Roles) AddRole(ctx context.Context, sender, shortName, roleType, filterA
 }
 
 func (r Roles) RemoveRole(ctx context.Context, sender, shortName string, sig bool) string {
+	ctx = r.WithSender(ctx, sender)
+
 	canPerform, err := r.Permissions.CanPerform(ctx, sender)
 	if err != nil {
 		return common.SendFatal(err.Error())
@@ -151,6 +156,18 @@ func (r Roles) RoleInfo(ctx context.Context, sender, shortName string, sig bool)
 	buffer.WriteString(fmt.Sprintf("Type: %s\n", info.Type))
 	buffer.WriteString(fmt.Sprintf("FilterA: %s\n", info.FilterA))
 	buffer.WriteString(fmt.Sprintf("FilterB: %s\n", info.FilterB))
+	if info.Expression != "" {
+		buffer.WriteString(fmt.Sprintf("Expression: %s\n", info.Expression))
+	}
+	if len(info.Parents) != 0 {
+		buffer.WriteString(fmt.Sprintf("Parents: %s\n", strings.Join(info.Parents, ", ")))
+	}
+	if len(info.Managers) != 0 {
+		buffer.WriteString(fmt.Sprintf("Managers: %s\n", strings.Join(info.Managers, ", ")))
+	}
+	if info.ManagerFilter != "" {
+		buffer.WriteString(fmt.Sprintf("Manager Filter: %s\n", info.ManagerFilter))
+	}
 	buffer.WriteString(fmt.Sprintf("Name: %s\n", info.Name))
 	buffer.WriteString(fmt.Sprintf("Color: %d\n", info.Color))
 	buffer.WriteString(fmt.Sprintf("Hoist: %t\n", info.Hoist))
@@ -158,8 +175,31 @@ func (r Roles) RoleInfo(ctx context.Context, sender, shortName string, sig bool)
 	buffer.WriteString(fmt.Sprintf("Permissions: %d\n", info.Permissions))
 	buffer.WriteString(fmt.Sprintf("Manged: %t\n", info.Managed))
 	buffer.WriteString(fmt.Sprintf("Mentionable: %t\n", info.Mentionable))
+	if info.DiscordId != "" {
+		buffer.WriteString(fmt.Sprintf("Discord Id: %s\n", info.DiscordId))
+	}
 	if sig {
 		buffer.WriteString(fmt.Sprintf("Joinable: %t\n", info.Joinable))
+		buffer.WriteString(fmt.Sprintf("Join Approval: %t\n", info.JoinApproval))
+	}
+	if info.Capacity != 0 {
+		members, err := r.RoleClient.GetRoleMembership(ctx, &rolesrv.RoleMembershipRequest{Name: shortName})
+		if err != nil {
+			return common.SendFatal(err.Error())
+		}
+
+		buffer.WriteString(fmt.Sprintf("Capacity: %d (%d members)\n", info.Capacity, len(members.Members)))
+	}
+	if info.Waitlist {
+		waitlist, err := r.RoleClient.GetWaitlist(ctx, &rolesrv.Role{ShortName: shortName})
+		if err != nil {
+			return common.SendFatal(err.Error())
+		}
+
+		buffer.WriteString(fmt.Sprintf("Waitlist: %d waiting\n", len(waitlist.Requests)))
+		for w, entry := range waitlist.Requests {
+			buffer.WriteString(fmt.Sprintf("\t%d. %s\n", w+1, entry.UserId))
+		}
 	}
 	buffer.WriteString(fmt.Sprintf("Sync: %t\n", info.Sync))
 
@@ -167,19 +207,125 @@ func (r Roles) RoleInfo(ctx context.Context, sender, shortName string, sig bool)
 }
 
 func (r Roles) SyncRoles(ctx context.Context, sender string) string {
+	ctx = r.WithSender(ctx, sender)
+
 	r.Logger.Info("Calling SyncRoles()")
 
-	_, err := r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, true))
+	job, err := r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, true))
 	if err != nil {
 		return common.SendFatal(err.Error())
 	}
 
+	if job.AlreadyQueued {
+		return common.SendSuccess("A sync is already queued, it will report here too")
+	}
+
 	return ""
 }
 
+// PlanSync shows what SyncRoles would change without changing anything.
+func (r Roles) PlanSync(ctx context.Context, sender string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	var buffer bytes.Buffer
+
+	canPerform, err := r.Permissions.CanPerform(ctx, sender)
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	if !canPerform {
+		return common.SendError("User doesn't have permission to this command")
+	}
+
+	plan, err := r.RoleClient.PlanSync(ctx, r.GetSyncRequest(sender, false))
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	for _, role := range plan.CreateRoles {
+		buffer.WriteString(fmt.Sprintf("Create role: %s\n", role))
+	}
+
+	for _, role := range plan.DeleteRoles {
+		buffer.WriteString(fmt.Sprintf("Delete role: %s\n", role))
+	}
+
+	for _, edit := range plan.EditRoles {
+		buffer.WriteString(fmt.Sprintf("Edit role: %s\n", edit.Name))
+		for _, change := range edit.Changes {
+			buffer.WriteString(fmt.Sprintf("\t%s: %s -> %s\n", change.Field, change.From, change.To))
+		}
+	}
+
+	for _, link := range plan.LinkRoles {
+		buffer.WriteString(fmt.Sprintf("Link role: %s to %s\n", link.ShortName, link.DiscordId))
+	}
+
+	for _, role := range plan.SkipRoles {
+		buffer.WriteString(fmt.Sprintf("Skip role: %s (more than one Discord role has this name)\n", role))
+	}
+
+	for _, member := range plan.Members {
+		buffer.WriteString(fmt.Sprintf("%s (%s):\n", member.Username, member.UserId))
+		if len(member.Add) != 0 {
+			buffer.WriteString(fmt.Sprintf("\t+ %s\n", strings.Join(member.Add, ", ")))
+		}
+		if len(member.Remove) != 0 {
+			buffer.WriteString(fmt.Sprintf("\t- %s\n", strings.Join(member.Remove, ", ")))
+		}
+	}
+
+	if buffer.Len() == 0 {
+		return common.SendSuccess("Nothing to sync\n")
+	}
+
+	return fmt.Sprintf("```%s```", buffer.String())
+}
+
+// ImportRoles creates Chremoas roles for the Discord roles it doesn't have
+// yet. With dryRun it only lists what it would create.
+func (r Roles) ImportRoles(ctx context.Context, sender string, dryRun bool) string {
+	ctx = r.WithSender(ctx, sender)
+
+	var buffer bytes.Buffer
+
+	canPerform, err := r.Permissions.CanPerform(ctx, sender)
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	if !canPerform {
+		return common.SendError("User doesn't have permission to this command")
+	}
+
+	imported, err := r.RoleClient.ImportRoles(ctx, &rolesrv.ImportRolesRequest{DryRun: dryRun})
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	for _, role := range imported.Imported {
+		buffer.WriteString(fmt.Sprintf("Import: %s as %s\n", role.Name, role.ShortName))
+	}
+
+	for _, skipped := range imported.Skipped {
+		buffer.WriteString(fmt.Sprintf("Skip: %s\n", skipped))
+	}
+
+	if buffer.Len() == 0 {
+		return common.SendSuccess("Nothing to import\n")
+	}
+
+	return fmt.Sprintf("```%s```", buffer.String())
+}
+
 func (r Roles) Set(ctx context.Context, sender, name, key, value string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	// role-srv checks permissions itself, which lets a role's managers
+	// change how it looks as well as admins
 	var validKeys = sets.NewStringSet()
-	validKeys.FromSlice([]string{"Color", "Hoist", "Position", "Permissions", "Managed", "Mentionable", "Sync"})
+	validKeys.FromSlice([]string{"Color", "Hoist", "Position", "Permissions", "Managed", "Mentionable", "Sync", "JoinApproval", "Capacity", "Waitlist", "Expression", "Parents"})
 
 	if !validKeys.Contains(key) {
 		var buffer bytes.Buffer
@@ -210,6 +356,46 @@ func (r Roles) Set(ctx context.Context, sender, name, key, value string) string
 	return common.SendSuccess(fmt.Sprintf("Set '%s' to '%s' for '%s'", key, value, name))
 }
 
+// AddManagers lets users, and the members of filter if it isn't empty, manage
+// a role.
+func (r Roles) AddManagers(ctx context.Context, sender, shortName string, users []string, filter string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	managers, err := r.RoleClient.AddRoleManagers(ctx, &rolesrv.RoleManagers{ShortName: shortName, Users: users, Filter: filter})
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	return r.formatManagers(managers)
+}
+
+// RemoveManagers stops users, and filter if it's the role's manager filter,
+// managing a role.
+func (r Roles) RemoveManagers(ctx context.Context, sender, shortName string, users []string, filter string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	managers, err := r.RoleClient.RemoveRoleManagers(ctx, &rolesrv.RoleManagers{ShortName: shortName, Users: users, Filter: filter})
+	if err != nil {
+		return common.SendFatal(err.Error())
+	}
+
+	return r.formatManagers(managers)
+}
+
+func (r Roles) formatManagers(managers *rolesrv.RoleManagers) string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(fmt.Sprintf("Managers of %s:\n", managers.ShortName))
+	for _, user := range managers.Users {
+		buffer.WriteString(fmt.Sprintf("\t%s\n", user))
+	}
+	if managers.Filter != "" {
+		buffer.WriteString(fmt.Sprintf("\tand everyone in %s\n", managers.Filter))
+	}
+
+	return fmt.Sprintf("```%s```", buffer.String())
+}
+
 func (r Roles) GetMembers(ctx context.Context, role string) string {
 	members, err := r.RoleClient.GetRoleMembership(ctx, &rolesrv.RoleMembershipRequest{Name: role})
 	if err != nil {
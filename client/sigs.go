@@ -1,11 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	rolesrv "github.com/chremoas/role-srv/proto"
 	common "github.com/chremoas/services-common/command"
 	"fmt"
 	"strings"
+	"time"
 )
 
 func (r Roles) AddSIG(ctx context.Context, sender, sig string) string {
@@ -25,6 +27,8 @@ func (r Roles) LeaveSIG(ctx context.Context, sender, sig string) string {
 }
 
 func (r Roles) sigAction(ctx context.Context, sender, sig string, join, joinable bool) string {
+	ctx = r.WithSender(ctx, sender)
+
 	s := strings.Split(sender, ":")
 
 	foo, err := r.RoleClient.GetRole(ctx, &rolesrv.Role{ShortName: sig})
@@ -42,24 +46,54 @@ func (r Roles) sigAction(ctx context.Context, sender, sig string, join, joinable
 		return common.SendError(err.Error())
 	}
 
-	// Is this a joinable role? Only check on Join/Leave not Add/Remove
+	// Joining checks whether it's joinable, and whether it's full, in
+	// role-srv. Only on Join/Leave not Add/Remove
 	if joinable {
-		if !role.Joinable {
-			return common.SendError(fmt.Sprintf("'%s' is not a joinable SIG, talk to an admin", sig))
+		result, err := r.RoleClient.JoinSIG(ctx, &rolesrv.JoinRequest{Role: sig, UserId: s[1], ChannelId: s[0]})
+		if err != nil {
+			return common.SendError(err.Error())
+		}
+
+		switch result.State {
+		case rolesrv.JoinState_REQUESTED:
+			return common.SendSuccess(fmt.Sprintf("Asked to join %s, a SIG manager will look at it", sig))
+		case rolesrv.JoinState_WAITLISTED:
+			return common.SendSuccess(fmt.Sprintf("%s is full, you're number %d on the waitlist", sig, result.Position))
+		}
+	}
+
+	// Leaving a SIG you're still waiting for takes you off the waitlist
+	if !join {
+		waitlist, err := r.RoleClient.GetWaitlist(ctx, &rolesrv.Role{ShortName: sig})
+		if err != nil {
+			return common.SendError(err.Error())
+		}
+
+		for _, entry := range waitlist.Requests {
+			if entry.UserId != s[1] {
+				continue
+			}
+
+			_, err = r.RoleClient.RemoveFromWaitlist(ctx, entry)
+			if err != nil {
+				return common.SendError(err.Error())
+			}
+
+			return common.SendSuccess(fmt.Sprintf("Took you off the waitlist for %s", sig))
 		}
 	}
 
 	// add member to role
-	if join {
+	if join && !joinable {
 		_, err = r.RoleClient.AddMembers(ctx, &rolesrv.Members{Name: []string{s[1]}, Filter: role.FilterB})
-	} else {
+	} else if !join {
 		_, err = r.RoleClient.RemoveMembers(ctx, &rolesrv.Members{Name: []string{s[1]}, Filter: role.FilterB})
 	}
 	if err != nil {
 		return common.SendError(err.Error())
 	}
 
-	_, err = r.RoleClient.SyncToChatService(ctx, r.GetSyncRequest(sender, false))
+	_, err = r.RoleClient.SyncToChatService(ctx, r.GetUserSyncRequest(sender, []string{s[1]}))
 	if err != nil {
 		return common.SendError(err.Error())
 	}
@@ -72,3 +106,56 @@ func (r Roles) sigAction(ctx context.Context, sender, sig string, join, joinable
 		return common.SendSuccess(fmt.Sprintf("Removed %s from %s", outputName[0], sig))
 	}
 }
+
+// ListJoinRequests shows the requests to join sig, or every SIG if it's
+// empty, that sender can decide.
+func (r Roles) ListJoinRequests(ctx context.Context, sender, sig string) string {
+	ctx = r.WithSender(ctx, sender)
+
+	var buffer bytes.Buffer
+
+	requests, err := r.RoleClient.GetJoinRequests(ctx, &rolesrv.JoinRequestQuery{Role: sig})
+	if err != nil {
+		return common.SendError(err.Error())
+	}
+
+	for _, request := range requests.Requests {
+		buffer.WriteString(fmt.Sprintf("%s: %s (%s)\n", request.Role, request.UserId,
+			time.Unix(request.Time, 0).UTC().Format(time.RFC3339)))
+	}
+
+	if buffer.Len() == 0 {
+		return common.SendSuccess("No join requests\n")
+	}
+
+	return fmt.Sprintf("```%s```", buffer.String())
+}
+
+func (r Roles) ApproveJoin(ctx context.Context, sender, sig, user string) string {
+	return r.decideJoin(ctx, sender, sig, user, true)
+}
+
+func (r Roles) DenyJoin(ctx context.Context, sender, sig, user string) string {
+	return r.decideJoin(ctx, sender, sig, user, false)
+}
+
+func (r Roles) decideJoin(ctx context.Context, sender, sig, user string, approve bool) string {
+	ctx = r.WithSender(ctx, sender)
+
+	var err error
+	request := &rolesrv.JoinRequest{Role: sig, UserId: user}
+	if approve {
+		_, err = r.RoleClient.ApproveJoinRequest(ctx, request)
+	} else {
+		_, err = r.RoleClient.DenyJoinRequest(ctx, request)
+	}
+	if err != nil {
+		return common.SendError(err.Error())
+	}
+
+	if approve {
+		return common.SendSuccess(fmt.Sprintf("Let %s into %s", user, sig))
+	} else {
+		return common.SendSuccess(fmt.Sprintf("Turned down %s for %s", user, sig))
+	}
+}
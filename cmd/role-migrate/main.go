@@ -0,0 +1,570 @@
+// role-migrate copies roles, filters, filter members and the no-sync list from
+// one storage backend to another and then checks that the two agree.
+//
+// The copy only ever adds, overwrites or removes individual entries, so it can
+// be run against a live source as many times as needed. The usual cut-over is
+// to run it once while role-srv is still on the old backend, switch
+// roles.storage over, then run it again to pick up anything that changed in
+// between.
+//
+// With -schema it instead moves the SQL schema to that version and exits. The
+// service only ever migrates up at startup, so this is the way to roll one
+// back.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/chremoas/role-srv/storage"
+	"github.com/chremoas/services-common/config"
+	redis "github.com/chremoas/services-common/redis"
+	"github.com/chremoas/services-common/sets"
+)
+
+func main() {
+	configFile := flag.String("configuration_file", "application.yaml", "path to the role-srv configuration file")
+	from := flag.String("from", "redis", "backend to copy from (redis or sql)")
+	to := flag.String("to", "sql", "backend to copy to (redis or sql)")
+	verifyOnly := flag.Bool("verify", false, "only compare the two backends, don't copy anything")
+	schema := flag.Int("schema", -1, "move the SQL schema to this version, rolling back if needed, and exit (0 is the newest)")
+	flag.Parse()
+
+	if *from == *to {
+		fail("-from and -to are the same backend")
+	}
+
+	conf := config.Configuration{}
+	if err := conf.Load(*configFile); err != nil {
+		fail(err)
+	}
+
+	redisClient := redis.Init(conf.LookupService("srv", "perms"))
+
+	if *schema >= 0 {
+		if err := migrateSchema(&conf, redisClient, *schema); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	source, err := open(*from, &conf, redisClient)
+	if err != nil {
+		fail(err)
+	}
+
+	if *to == "sql" && !*verifyOnly {
+		if err := checkNames(source); err != nil {
+			fail(err)
+		}
+	}
+
+	target, err := open(*to, &conf, redisClient)
+	if err != nil {
+		fail(err)
+	}
+
+	if !*verifyOnly {
+		if err := copyStore(source, target); err != nil {
+			fail(err)
+		}
+	}
+
+	problems, err := verify(source, target)
+	if err != nil {
+		fail(err)
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("Verification failed: %d difference(s)\n", len(problems))
+		os.Exit(1)
+	}
+
+	fmt.Println("Verification passed")
+}
+
+func fail(v interface{}) {
+	fmt.Fprintln(os.Stderr, v)
+	os.Exit(1)
+}
+
+func open(backend string, conf *config.Configuration, redisClient *redis.Client) (storage.Store, error) {
+	if backend != "redis" && backend != "sql" {
+		return nil, fmt.Errorf("unsupported backend: %s", backend)
+	}
+
+	store, err := storage.Open(backend, conf, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrator, ok := store.(storage.Migrator); ok {
+		steps, err := migrator.Migrate()
+		for _, step := range steps {
+			fmt.Printf("Applied schema migration: %s\n", step)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// migrateSchema moves the SQL schema to target, whichever way that is.
+func migrateSchema(conf *config.Configuration, redisClient *redis.Client, target int) error {
+	store, err := storage.Open("sql", conf, redisClient)
+	if err != nil {
+		return err
+	}
+
+	steps, err := store.(*storage.SQLStore).MigrateTo(target)
+	for _, step := range steps {
+		fmt.Printf("Applied schema migration: %s\n", step)
+	}
+
+	return err
+}
+
+// checkNames finds every display name used by more than one role. Redis
+// allows that, but the SQL schema has a unique index on roles.name, so
+// copying them would fail partway through. MySQL compares names without case
+// or trailing spaces, so those count as the same name too.
+func checkNames(source storage.Store) error {
+	roles, err := source.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string][]string)
+	for _, role := range roles {
+		fields, err := source.GetRole(role)
+		if err != nil {
+			return err
+		}
+
+		name := strings.ToLower(strings.TrimRight(fields["Name"], " "))
+		byName[name] = append(byName[name], fmt.Sprintf("%s (%q)", role, fields["Name"]))
+	}
+
+	var conflicts []string
+	for _, roles := range byName {
+		if len(roles) > 1 {
+			sort.Strings(roles)
+			conflicts = append(conflicts, strings.Join(roles, ", "))
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	for _, c := range conflicts {
+		fmt.Printf("Roles sharing a name: %s\n", c)
+	}
+
+	return fmt.Errorf("%d role name(s) are used more than once, rename them before copying to sql", len(conflicts))
+}
+
+func copyStore(source, target storage.Store) error {
+	roles, err := source.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	// Roles that are gone go first, so their names are free for the rest
+	targetRoles, err := target.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range difference(targetRoles, roles) {
+		if err := target.DeleteRole(role); err != nil {
+			return fmt.Errorf("role %s: %s", role, err)
+		}
+	}
+
+	for _, role := range roles {
+		fields, err := source.GetRole(role)
+		if err != nil {
+			return err
+		}
+
+		if err := target.SetRole(role, fields); err != nil {
+			return fmt.Errorf("role %s: %s", role, err)
+		}
+	}
+
+	fmt.Printf("Copied %d roles\n", len(roles))
+
+	filters, err := source.GetFilters()
+	if err != nil {
+		return err
+	}
+
+	for filter, description := range filters {
+		if err := target.SetFilter(filter, description); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+
+		members, err := source.GetFilterMembers(filter)
+		if err != nil {
+			return err
+		}
+
+		targetMembers, err := target.GetFilterMembers(filter)
+		if err != nil {
+			return err
+		}
+
+		if err := target.AddFilterMembers(filter, members); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+
+		if err := target.RemoveFilterMembers(filter, difference(targetMembers, members)); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+
+		expiries, err := source.GetMemberExpiries(filter)
+		if err != nil {
+			return err
+		}
+
+		targetExpiries, err := target.GetMemberExpiries(filter)
+		if err != nil {
+			return err
+		}
+
+		for member := range targetExpiries {
+			if _, ok := expiries[member]; !ok {
+				expiries[member] = 0
+			}
+		}
+
+		if err := target.SetMemberExpiries(filter, expiries); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+	}
+
+	targetFilters, err := target.GetFilters()
+	if err != nil {
+		return err
+	}
+
+	for filter := range targetFilters {
+		if _, ok := filters[filter]; ok {
+			continue
+		}
+
+		members, err := target.GetFilterMembers(filter)
+		if err != nil {
+			return err
+		}
+
+		if err := target.RemoveFilterMembers(filter, members); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+
+		if err := target.DeleteFilter(filter); err != nil {
+			return fmt.Errorf("filter %s: %s", filter, err)
+		}
+	}
+
+	fmt.Printf("Copied %d filters\n", len(filters))
+
+	noSync, err := source.GetNoSync()
+	if err != nil {
+		return err
+	}
+
+	targetNoSync, err := target.GetNoSync()
+	if err != nil {
+		return err
+	}
+
+	if err := target.AddNoSync(noSync); err != nil {
+		return err
+	}
+
+	if err := target.RemoveNoSync(difference(targetNoSync, noSync)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d no-sync members\n", len(noSync))
+
+	requests, err := copyRequests("join request", source.GetJoinRequests, target.GetJoinRequests,
+		target.AddJoinRequest, target.RemoveJoinRequest)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d join requests\n", requests)
+
+	waiting, err := copyRequests("waitlist entry", source.GetWaitlist, target.GetWaitlist,
+		target.AddToWaitlist, target.RemoveFromWaitlist)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d waitlist entries\n", waiting)
+
+	return nil
+}
+
+// copyRequests replaces every join request, or waitlist entry, in the target
+// with those in the source and returns how many it copied.
+func copyRequests(kind string, get, getTarget func(string) ([]storage.JoinRequest, error),
+	add func(*storage.JoinRequest) error, remove func(string, string) error) (int, error) {
+	requests, err := get("")
+	if err != nil {
+		return 0, err
+	}
+
+	targetRequests, err := getTarget("")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, request := range targetRequests {
+		if err := remove(request.Role, request.User); err != nil {
+			return 0, fmt.Errorf("%s %s/%s: %s", kind, request.Role, request.User, err)
+		}
+	}
+
+	for r := range requests {
+		if err := add(&requests[r]); err != nil {
+			return 0, fmt.Errorf("%s %s/%s: %s", kind, requests[r].Role, requests[r].User, err)
+		}
+	}
+
+	return len(requests), nil
+}
+
+// verify compares the two stores and returns a line for every difference.
+func verify(source, target storage.Store) ([]string, error) {
+	var problems []string
+
+	roles, err := source.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	targetRoles, err := target.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roles) != len(targetRoles) {
+		problems = append(problems, fmt.Sprintf("role count: %d != %d", len(roles), len(targetRoles)))
+	}
+
+	for _, role := range difference(targetRoles, roles) {
+		problems = append(problems, fmt.Sprintf("role %s: only in target", role))
+	}
+
+	for _, role := range roles {
+		fields, err := source.GetRole(role)
+		if err != nil {
+			return nil, err
+		}
+
+		targetFields, err := target.GetRole(role)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(targetFields) == 0 {
+			problems = append(problems, fmt.Sprintf("role %s: missing from target", role))
+			continue
+		}
+
+		for _, field := range fieldNames(fields, targetFields) {
+			if normalize(fields[field]) != normalize(targetFields[field]) {
+				problems = append(problems, fmt.Sprintf("role %s: %s is %q, target has %q",
+					role, field, fields[field], targetFields[field]))
+			}
+		}
+	}
+
+	filters, err := source.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	targetFilters, err := target.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filters) != len(targetFilters) {
+		problems = append(problems, fmt.Sprintf("filter count: %d != %d", len(filters), len(targetFilters)))
+	}
+
+	for filter := range targetFilters {
+		if _, ok := filters[filter]; !ok {
+			problems = append(problems, fmt.Sprintf("filter %s: only in target", filter))
+		}
+	}
+
+	for filter, description := range filters {
+		targetDescription, ok := targetFilters[filter]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("filter %s: missing from target", filter))
+			continue
+		}
+
+		if description != targetDescription {
+			problems = append(problems, fmt.Sprintf("filter %s: description is %q, target has %q",
+				filter, description, targetDescription))
+		}
+
+		members, err := source.GetFilterMembers(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		targetMembers, err := target.GetFilterMembers(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, compareSets(fmt.Sprintf("filter %s", filter), members, targetMembers)...)
+
+		expiries, err := source.GetMemberExpiries(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		targetExpiries, err := target.GetMemberExpiries(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for member, expiry := range expiries {
+			if targetExpiries[member] != expiry {
+				problems = append(problems, fmt.Sprintf("filter %s: %s expires at %d, target has %d",
+					filter, member, expiry, targetExpiries[member]))
+			}
+		}
+
+		for member, expiry := range targetExpiries {
+			if _, ok := expiries[member]; !ok {
+				problems = append(problems, fmt.Sprintf("filter %s: %s expires at %d only in target",
+					filter, member, expiry))
+			}
+		}
+	}
+
+	noSync, err := source.GetNoSync()
+	if err != nil {
+		return nil, err
+	}
+
+	targetNoSync, err := target.GetNoSync()
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, compareSets("no-sync", noSync, targetNoSync)...)
+
+	requests, err := source.GetJoinRequests("")
+	if err != nil {
+		return nil, err
+	}
+
+	targetRequests, err := target.GetJoinRequests("")
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, compareSets("join requests", joinRequestNames(requests), joinRequestNames(targetRequests))...)
+
+	waiting, err := source.GetWaitlist("")
+	if err != nil {
+		return nil, err
+	}
+
+	targetWaiting, err := target.GetWaitlist("")
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, compareSets("waitlist", joinRequestNames(waiting), joinRequestNames(targetWaiting))...)
+
+	return problems, nil
+}
+
+// joinRequestNames identifies each join request or waitlist entry as
+// role/user.
+func joinRequestNames(requests []storage.JoinRequest) []string {
+	names := make([]string, len(requests))
+	for r := range requests {
+		names[r] = requests[r].Role + "/" + requests[r].User
+	}
+	return names
+}
+
+func compareSets(name string, source, target []string) []string {
+	var problems []string
+
+	if len(source) != len(target) {
+		problems = append(problems, fmt.Sprintf("%s: member count %d != %d", name, len(source), len(target)))
+	}
+
+	for _, m := range difference(source, target) {
+		problems = append(problems, fmt.Sprintf("%s: %s missing from target", name, m))
+	}
+
+	for _, m := range difference(target, source) {
+		problems = append(problems, fmt.Sprintf("%s: %s only in target", name, m))
+	}
+
+	return problems
+}
+
+// difference returns the entries of a that aren't in b, sorted.
+func difference(a, b []string) []string {
+	setA := sets.NewStringSet()
+	setA.FromSlice(a)
+	setB := sets.NewStringSet()
+	setB.FromSlice(b)
+
+	list := setA.Difference(setB).ToSlice()
+	sort.Strings(list)
+	return list
+}
+
+func fieldNames(a, b map[string]string) []string {
+	names := sets.NewStringSet()
+	for k := range a {
+		names.Add(k)
+	}
+	for k := range b {
+		names.Add(k)
+	}
+
+	list := names.ToSlice()
+	sort.Strings(list)
+	return list
+}
+
+// normalize smooths over the ways the backends spell the same value. Redis
+// keeps whatever string was written (bools as "true" or "1", unset numbers as
+// "") while SQL always hands back "0"/"1" and a number.
+func normalize(value string) string {
+	switch value {
+	case "", "false":
+		return "0"
+	case "true":
+		return "1"
+	}
+	return value
+}
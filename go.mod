@@ -6,13 +6,17 @@ require (
 	github.com/chremoas/discord-gateway v1.3.0
 	github.com/chremoas/perms-srv v1.3.0
 	github.com/chremoas/services-common v1.3.1
-	github.com/fatih/structs v1.1.0
+	github.com/go-redis/redis v6.15.2+incompatible
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/golang/protobuf v1.3.2
+	github.com/google/uuid v1.1.1
 	github.com/micro/go-micro v1.9.1
 	github.com/prometheus/common v0.6.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/spf13/viper v1.4.0
 	go.uber.org/zap v1.10.0
 	golang.org/x/net v0.0.0-20190724013045-ca1201d0de80
+	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 )
 
 replace github.com/chremoas/role-srv => ../role-srv
@@ -4,12 +4,13 @@ import (
 	"errors"
 	"fmt"
 	discord "github.com/chremoas/discord-gateway/proto"
+	permsrv "github.com/chremoas/perms-srv/proto"
 	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
 	common "github.com/chremoas/services-common/command"
 	"github.com/chremoas/services-common/config"
 	redis "github.com/chremoas/services-common/redis"
 	"github.com/chremoas/services-common/sets"
-	"github.com/fatih/structs"
 	"github.com/micro/go-micro"
 	"github.com/micro/go-micro/client"
 	"github.com/prometheus/common/log"
@@ -17,6 +18,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,23 +27,21 @@ import (
 type rolesHandler struct {
 	Client client.Client
 	Redis  *redis.Client
+	Store  storage.Store
 	Logger *zap.Logger
 }
 
 type clientList struct {
 	discord discord.DiscordGatewayService
+	perms   permsrv.PermissionsService
 }
 
-type syncData struct {
-	ChannelId   string
-	UserId      string
-	SendMessage bool
-}
-
-var syncControl chan syncData
+var syncControl *syncQueue
+var syncLeader *syncLock
+var syncJobs *syncJobList
 var clients clientList
 var ignoredRoles []string
-var roleKeys = []string{"Name", "Color", "Hoist", "Position", "Permissions", "Managed", "Mentionable", "Sync"}
+var roleKeys = []string{"Name", "Color", "Hoist", "Position", "Permissions", "Managed", "Mentionable", "Sync", "JoinApproval", "Capacity", "Waitlist", "Expression", "Parents"}
 var roleTypes = []string{"internal", "discord"}
 
 func NewRolesHandler(config *config.Configuration, service micro.Service, log *zap.Logger) rolesrv.RolesHandler {
@@ -49,8 +49,12 @@ func NewRolesHandler(config *config.Configuration, service micro.Service, log *z
 
 	clients = clientList{
 		discord: discord.NewDiscordGatewayService(config.LookupService("gateway", "discord"), c),
+		perms:   permsrv.NewPermissionsService(config.LookupService("srv", "perms"), c),
 	}
 
+	serviceName = config.LookupService("srv", "role")
+	enforcePermissions = !viper.IsSet("roles.enforcePermissions") || viper.GetBool("roles.enforcePermissions")
+
 	ignoredRoles = viper.GetStringSlice("bot.ignoredRoles")
 
 	redisClient := redis.Init(config.LookupService("srv", "perms"))
@@ -89,41 +93,57 @@ func NewRolesHandler(config *config.Configuration, service micro.Service, log *z
 		}
 	}
 
-	rh := &rolesHandler{Redis: redisClient, Logger: log}
+	store, err := storage.Open(viper.GetString("roles.storage"), config, redisClient)
+	if err != nil {
+		panic(err)
+	}
+
+	rh := &rolesHandler{Redis: redisClient, Store: store, Logger: log}
 
-	// Check and update Redis schema as needed
+	// Check and update the store schema as needed
 	rh.updateSchema()
 
 	// Start sync thread
-	syncControl = make(chan syncData, 30)
+	syncControl = newSyncQueue()
+	syncLeader = newSyncLock(redisClient, log)
+	syncJobs = &syncJobList{limit: viper.GetInt("roles.syncHistory"), redis: redisClient, logger: log}
+	if syncJobs.limit <= 0 {
+		syncJobs.limit = 50
+	}
 	go rh.syncThread()
 
+	// Start the thread that takes expired members out of filters
+	go rh.expiryThread()
+
+	// Start the thread that lets people off SIG waitlists
+	go rh.promoteThread()
+
+	// Start scheduled syncs, if there's a schedule
+	if spec := viper.GetString("roles.syncSchedule"); spec != "" {
+		schedule, err := parseSyncSchedule(spec)
+		if err != nil {
+			panic(err)
+		}
+		go rh.scheduleThread(schedule)
+	}
+
 	return rh
 }
 
+// updateSchema brings the store's schema up to date before anything touches
+// it. A store whose schema is newer than this build understands is fatal.
 func (h *rolesHandler) updateSchema() {
-	sugar := h.Logger.Sugar()
-
-	// Update Roles hash
-	roles, err := h.getRoles()
-
-	if err != nil {
-		sugar.Errorf("Something went wrong getting the Roles: %s", err)
+	migrator, ok := h.Store.(storage.Migrator)
+	if !ok {
 		return
 	}
 
-	for role := range roles {
-		roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", roles[role]))
-		roleInfo, err := h.Redis.Client.HGetAll(roleName).Result()
-		if err != nil {
-			sugar.Errorf("Something went wrong getting the hash from Redis: %s", err)
-			return
-		}
-
-		if roleInfo["Sync"] == "" {
-			sugar.Infof("Updating role (%s) with default Sync value", roles[role])
-			h.Redis.Client.HSet(roleName, "Sync", "1")
-		}
+	applied, err := migrator.Migrate()
+	for _, step := range applied {
+		h.Logger.Sugar().Infof("Applied schema migration: %s", step)
+	}
+	if err != nil {
+		panic(err)
 	}
 }
 
@@ -138,11 +158,11 @@ func (h *rolesHandler) GetRoleTypes(ctx context.Context, request *rolesrv.NilMes
 }
 
 func (h *rolesHandler) AddRole(ctx context.Context, request *rolesrv.Role, response *rolesrv.NilMessage) error {
-	roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", request.ShortName))
-	filterA := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.FilterA))
-	filterB := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.FilterB))
+	if err := h.authorize(ctx, "AddRole", adminsFor(request.Sig)); err != nil {
+		return err
+	}
 
-	// Type, Name and the filters are required so let's check for those
+	// Type, Name and either the filters or an expression are required so let's check for those
 	if len(request.Type) == 0 {
 		return errors.New("type is required")
 	}
@@ -155,11 +175,11 @@ func (h *rolesHandler) AddRole(ctx context.Context, request *rolesrv.Role, respo
 		return errors.New("Name is required")
 	}
 
-	if len(request.FilterA) == 0 {
+	if len(request.FilterA) == 0 && len(request.Expression) == 0 {
 		return errors.New("FilterA is required")
 	}
 
-	if len(request.FilterB) == 0 {
+	if len(request.FilterB) == 0 && len(request.Expression) == 0 {
 		return errors.New("FilterB is required")
 	}
 
@@ -167,70 +187,62 @@ func (h *rolesHandler) AddRole(ctx context.Context, request *rolesrv.Role, respo
 		return fmt.Errorf("`%s` isn't a valid Role Type", request.Type)
 	}
 
-	exists, err := h.Redis.Client.Exists(roleName).Result()
-
-	if err != nil {
-		return err
+	// The wildcard filter isn't a real filter so there's nothing to check for it
+	var filters []string
+	if request.FilterA != "" && request.FilterA != "wildcard" {
+		filters = append(filters, request.FilterA)
 	}
-
-	if exists == 1 {
-		return fmt.Errorf("Role `%s` already exists.", request.Name)
+	if request.FilterB != "" && request.FilterB != "wildcard" {
+		filters = append(filters, request.FilterB)
 	}
 
-	// Check if filter A exists
-	exists, err = h.Redis.Client.Exists(filterA).Result()
+	if len(request.Expression) != 0 {
+		expression, err := parseExpression(request.Expression)
+		if err != nil {
+			return fmt.Errorf("Invalid Expression: %s", err)
+		}
 
-	if err != nil {
-		return err
+		filters = append(filters, expressionFilters(expression)...)
 	}
 
-	if exists == 0 && request.FilterA != "wildcard" {
-		return fmt.Errorf("FilterA `%s` doesn't exists.", request.FilterA)
+	if len(request.ManagerFilter) != 0 {
+		filters = append(filters, request.ManagerFilter)
 	}
 
-	// Check if filter B exists
-	exists, err = h.Redis.Client.Exists(filterB).Result()
-
-	if err != nil {
+	if err := h.checkParents(request.ShortName, request.Parents); err != nil {
 		return err
 	}
 
-	if exists == 0 && request.FilterB != "wildcard" {
-		return fmt.Errorf("FilterB `%s` doesn't exists.", request.FilterB)
-	}
-
-	_, err = h.Redis.Client.HMSet(roleName, structs.Map(request)).Result()
+	err := h.Store.CreateRole(request.ShortName, mapProtobufRoleToRole(request), filters)
 
-	if err != nil {
+	var missingFilter *storage.MissingFilterError
+	switch {
+	case err == storage.ErrRoleExists:
+		return fmt.Errorf("Role `%s` already exists.", request.Name)
+	case errors.As(err, &missingFilter):
+		switch missingFilter.Name {
+		case request.FilterA:
+			return fmt.Errorf("FilterA `%s` doesn't exists.", request.FilterA)
+		case request.FilterB:
+			return fmt.Errorf("FilterB `%s` doesn't exists.", request.FilterB)
+		case request.ManagerFilter:
+			return fmt.Errorf("ManagerFilter `%s` doesn't exists.", request.ManagerFilter)
+		default:
+			return fmt.Errorf("Filter `%s` in Expression doesn't exists.", missingFilter.Name)
+		}
+	case err != nil:
 		return err
 	}
 
+	h.audit(actingUser(ctx), "AddRole", request.ShortName, nil, mapProtobufRoleToRole(request))
+
 	response = &rolesrv.NilMessage{}
 
 	return nil
 }
 
 func (h *rolesHandler) UpdateRole(ctx context.Context, request *rolesrv.UpdateInfo, response *rolesrv.NilMessage) error {
-	// Does this actually work? -brian
-	roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", request.Name))
-
-	exists, err := h.Redis.Client.Exists(roleName).Result()
-
-	if err != nil {
-		return err
-	}
-
-	if exists == 0 {
-		return fmt.Errorf("Role `%s` doesn't exists.", request.Name)
-	}
-
-	if !validListItem(request.Key, roleKeys) {
-		return fmt.Errorf("`%s` isn't a valid Role Key.", request.Key)
-	}
-
-	h.Redis.Client.HSet(roleName, request.Key, request.Value)
-
-	return nil
+	return h.updateRole(ctx, "UpdateRole", request.Name, map[string]string{request.Key: request.Value})
 }
 
 func validListItem(a string, list []string) bool {
@@ -243,24 +255,38 @@ func validListItem(a string, list []string) bool {
 }
 
 func (h *rolesHandler) RemoveRole(ctx context.Context, request *rolesrv.Role, response *rolesrv.NilMessage) error {
-	roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", request.ShortName))
+	if err := h.authorizeRole(ctx, "RemoveRole", request.ShortName); err != nil {
+		return err
+	}
 
-	exists, err := h.Redis.Client.Exists(roleName).Result()
+	before, err := h.getRole(request.ShortName)
+	if err != nil {
+		return err
+	}
 
+	// Children would be left naming a parent that isn't there
+	children, err := h.childRoles(request.ShortName)
 	if err != nil {
 		return err
 	}
 
-	if exists == 0 {
-		return fmt.Errorf("Role `%s` doesn't exists.", request.ShortName)
+	if len(children) > 0 {
+		return fmt.Errorf("Role `%s` is a parent of %s, take it out of their Parents first.",
+			request.ShortName, strings.Join(children, ", "))
 	}
 
-	_, err = h.Redis.Client.Del(roleName).Result()
+	err = h.Store.RemoveRole(request.ShortName)
+
+	if err == storage.ErrRoleNotFound {
+		return fmt.Errorf("Role `%s` doesn't exists.", request.ShortName)
+	}
 
 	if err != nil {
 		return err
 	}
 
+	h.audit(actingUser(ctx), "RemoveRole", request.ShortName, before, nil)
+
 	response = &rolesrv.NilMessage{}
 	return nil
 }
@@ -274,7 +300,7 @@ func (h *rolesHandler) GetRoles(ctx context.Context, request *rolesrv.NilMessage
 	}
 
 	for role := range roles {
-		roleInfo, err := h.Redis.Client.HGetAll(h.Redis.KeyName(fmt.Sprintf("role:%s", roles[role]))).Result()
+		roleInfo, err := h.Store.GetRole(roles[role])
 		if err != nil {
 			return err
 		}
@@ -298,11 +324,12 @@ func (h *rolesHandler) GetRoles(ctx context.Context, request *rolesrv.NilMessage
 		}
 
 		response.Roles = append(response.Roles, &rolesrv.Role{
-			ShortName: roles[role],
-			Name:      roleInfo["Name"],
-			Sig:       sigValue,
-			Joinable:  joinableValue,
-			Sync:      syncValue,
+			ShortName:    roles[role],
+			Name:         roleInfo["Name"],
+			Sig:          sigValue,
+			Joinable:     joinableValue,
+			JoinApproval: isSet(roleInfo["JoinApproval"]),
+			Sync:         syncValue,
 		})
 	}
 
@@ -310,34 +337,20 @@ func (h *rolesHandler) GetRoles(ctx context.Context, request *rolesrv.NilMessage
 }
 
 func (h *rolesHandler) getRoles() ([]string, error) {
-	var roleList []string
-	roles, err := h.Redis.Client.Keys(h.Redis.KeyName("role:*")).Result()
-
-	if err != nil {
-		return nil, err
-	}
-
-	for role := range roles {
-		roleName := strings.Split(roles[role], ":")
-		roleList = append(roleList, roleName[len(roleName)-1])
-	}
-
-	return roleList, nil
+	return h.Store.GetRoles()
 }
 
 func (h *rolesHandler) getRole(name string) (role map[string]string, err error) {
-	roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", name))
-
-	exists, err := h.Redis.Client.Exists(roleName).Result()
+	exists, err := h.Store.RoleExists(name)
 	if err != nil {
 		return nil, err
 	}
 
-	if exists == 0 {
+	if !exists {
 		return nil, fmt.Errorf("role doesn't exist: %s", name)
 	}
 
-	r, err := h.Redis.Client.HGetAll(roleName).Result()
+	r, err := h.Store.GetRole(name)
 	if err != nil {
 		return nil, err
 	}
@@ -354,24 +367,70 @@ func (h *rolesHandler) mapRoleToProtobufRole(role map[string]string) *rolesrv.Ro
 	mentionable, _ := strconv.ParseBool(role["Mentionable"])
 	sig, _ := strconv.ParseBool(role["Sig"])
 	joinable, _ := strconv.ParseBool(role["Joinable"])
+	joinApproval, _ := strconv.ParseBool(role["JoinApproval"])
+	capacity, _ := strconv.ParseInt(role["Capacity"], 10, 32)
+	waitlist, _ := strconv.ParseBool(role["Waitlist"])
 	sync, _ := strconv.ParseBool(role["Sync"])
 
 	return &rolesrv.Role{
-		ShortName:   role["ShortName"],
-		Type:        role["Type"],
-		FilterA:     role["FilterA"],
-		FilterB:     role["FilterB"],
-		Name:        role["Name"],
-		Color:       int32(color),
-		Hoist:       hoist,
-		Position:    int32(position),
-		Permissions: int32(permissions),
-		Managed:     managed,
-		Mentionable: mentionable,
-		Sig:         sig,
-		Joinable:    joinable,
-		Sync:        sync,
+		ShortName:     role["ShortName"],
+		Type:          role["Type"],
+		FilterA:       role["FilterA"],
+		FilterB:       role["FilterB"],
+		Expression:    role["Expression"],
+		Parents:       roleParents(role),
+		Managers:      commaList(role["Managers"]),
+		ManagerFilter: role["ManagerFilter"],
+		Name:          role["Name"],
+		Color:         int32(color),
+		Hoist:         hoist,
+		Position:      int32(position),
+		Permissions:   int32(permissions),
+		Managed:       managed,
+		Mentionable:   mentionable,
+		DiscordId:     role["DiscordId"],
+		Sig:           sig,
+		Joinable:      joinable,
+		JoinApproval:  joinApproval,
+		Capacity:      int32(capacity),
+		Waitlist:      waitlist,
+		Sync:          sync,
+	}
+}
+
+func mapProtobufRoleToRole(role *rolesrv.Role) map[string]string {
+	return map[string]string{
+		"ShortName":     role.ShortName,
+		"Type":          role.Type,
+		"FilterA":       role.FilterA,
+		"FilterB":       role.FilterB,
+		"Expression":    role.Expression,
+		"Parents":       strings.Join(role.Parents, ","),
+		"Managers":      strings.Join(role.Managers, ","),
+		"ManagerFilter": role.ManagerFilter,
+		"Name":          role.Name,
+		"Color":         strconv.Itoa(int(role.Color)),
+		"Hoist":         boolToString(role.Hoist),
+		"Position":      strconv.Itoa(int(role.Position)),
+		"Permissions":   strconv.Itoa(int(role.Permissions)),
+		"Managed":       boolToString(role.Managed),
+		"Mentionable":   boolToString(role.Mentionable),
+		"DiscordId":     role.DiscordId,
+		"Sig":           boolToString(role.Sig),
+		"Joinable":      boolToString(role.Joinable),
+		"JoinApproval":  boolToString(role.JoinApproval),
+		"Capacity":      strconv.Itoa(int(role.Capacity)),
+		"Waitlist":      boolToString(role.Waitlist),
+		"Sync":          boolToString(role.Sync),
+	}
+}
+
+// boolToString matches how go-redis has always written bools into the role hash.
+func boolToString(b bool) string {
+	if b {
+		return "1"
 	}
+	return "0"
 }
 
 func (h *rolesHandler) GetRole(ctx context.Context, request *rolesrv.Role, response *rolesrv.Role) error {
@@ -396,85 +455,112 @@ func (h *rolesHandler) sendMessage(ctx context.Context, channelId, message strin
 	}
 }
 
-func (h *rolesHandler) syncMembers(channelId, userId string, sendMessage bool) error {
+// memberPlan is what syncMembers would do to Discord: the full set of
+// Discord role IDs every user who is out of step should end up with. Roles
+// that don't exist on Discord yet appear by name instead of ID.
+type memberPlan struct {
+	changes   []*memberChange
+	roleNames map[string]string
+	username  map[string]string
+	// membership of every synced role, by ShortName
+	membership map[string]*sets.StringSet
+}
+
+// memberChange is one user's new roles; add and remove are role names.
+type memberChange struct {
+	userId string
+	roles  *sets.StringSet
+	add    []string
+	remove []string
+}
+
+// planMembers works out which Discord users need their roles changed without
+// changing anything. If users isn't nil only those users are looked at.
+func (h *rolesHandler) planMembers(request syncData, users *sets.StringSet) (*memberPlan, error) {
 	sugar := h.Logger.Sugar()
 	var roleNameMap = make(map[string]string)
+	var roleIdMap = make(map[string]string)
 	var idToNameMap = make(map[string]string)
 	var discordMemberships = make(map[string]*sets.StringSet)
 	var chremoasMemberships = make(map[string]*sets.StringSet)
-	var updateMembers = make(map[string]*sets.StringSet)
+	var managed = sets.NewStringSet()
+	var plan = &memberPlan{roleNames: roleIdMap, username: idToNameMap, membership: make(map[string]*sets.StringSet)}
 
-	// Discord limit is 1000, should probably make this a config option. -brian
-	var numberPerPage int32 = 1000
-	var memberCount = 1
-	var memberId = ""
-
-	t := time.Now()
+	addMember := func(member *discord.Member) {
+		userId := member.User.Id
+		if _, ok := discordMemberships[userId]; !ok {
+			discordMemberships[userId] = sets.NewStringSet()
+		}
 
-	// Need to pre-populate the membership sets with all the users so we can pick up users with no roles.
-	for memberCount > 0 {
-		//longCtx, _ := context.WithTimeout(context.Background(), time.Second * 20)
+		idToNameMap[userId] = member.User.Username
 
-		members, err := clients.discord.GetAllMembers(context.Background(), &discord.GetAllMembersRequest{NumberPerPage: numberPerPage, After: memberId})
-		if err != nil {
-			msg := fmt.Sprintf("syncMembers: GetAllMembers: %s", err.Error())
-			h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
-			sugar.Error(msg)
-			return err
+		for r := range member.Roles {
+			discordMemberships[userId].Add(member.Roles[r].Id)
 		}
 
-		for m := range members.Members {
-			userId := members.Members[m].User.Id
-			if _, ok := discordMemberships[userId]; !ok {
-				discordMemberships[userId] = sets.NewStringSet()
-			}
+		if _, ok := chremoasMemberships[userId]; !ok {
+			chremoasMemberships[userId] = sets.NewStringSet()
+		}
+	}
 
-			idToNameMap[userId] = members.Members[m].User.Username
+	t := time.Now()
 
-			for r := range members.Members[m].Roles {
-				discordMemberships[userId].Add(members.Members[m].Roles[r].Name)
+	if users != nil {
+		// A limited sync only looks the users it needs up.
+		for userId := range users.Set {
+			member, err := getDiscordMember(context.Background(), userId)
+			if err != nil {
+				msg := fmt.Sprintf("planMembers: getDiscordMember: %s", err.Error())
+				h.sendFatal(msg, request)
+				sugar.Error(msg)
+				return nil, err
 			}
 
-			if _, ok := chremoasMemberships[userId]; !ok {
-				chremoasMemberships[userId] = sets.NewStringSet()
+			if member != nil {
+				addMember(member)
 			}
+		}
+	}
 
-			oldNum, _ := strconv.Atoi(members.Members[m].User.Id)
-			newNum, _ := strconv.Atoi(memberId)
-
-			if oldNum > newNum {
-				memberId = members.Members[m].User.Id
-			}
+	// Need to pre-populate the membership sets with all the users so we can pick up users with no roles.
+	if users == nil {
+		members, err := getAllDiscordMembers(context.Background())
+		if err != nil {
+			msg := fmt.Sprintf("planMembers: GetAllMembers: %s", err.Error())
+			h.sendFatal(msg, request)
+			sugar.Error(msg)
+			return nil, err
 		}
 
-		memberCount = len(members.Members)
+		for m := range members {
+			addMember(members[m])
+		}
 	}
 
 	h.sendDualMessage(
 		fmt.Sprintf("Got all Discord members [%s]", time.Since(t)),
-		channelId,
-		sendMessage,
+		request,
 	)
 
 	t = time.Now()
 
-	// Get all the Roles from discord and create a map of their name to their Id
+	// Get all the Roles from discord and map their names and Ids both ways
 	discordRoles, err := clients.discord.GetAllRoles(context.Background(), &discord.GuildObjectRequest{})
 	if err != nil {
-		msg := fmt.Sprintf("syncMembers: GetAllRoles: %s", err.Error())
-		h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
+		msg := fmt.Sprintf("planMembers: GetAllRoles: %s", err.Error())
+		h.sendFatal(msg, request)
 		sugar.Error(msg)
-		return err
+		return nil, err
 	}
 
 	for d := range discordRoles.Roles {
 		roleNameMap[discordRoles.Roles[d].Name] = discordRoles.Roles[d].Id
+		roleIdMap[discordRoles.Roles[d].Id] = discordRoles.Roles[d].Name
 	}
 
 	h.sendDualMessage(
 		fmt.Sprintf("Got all Discord roles [%s]", time.Since(t)),
-		channelId,
-		sendMessage,
+		request,
 	)
 
 	t = time.Now()
@@ -482,51 +568,70 @@ func (h *rolesHandler) syncMembers(channelId, userId string, sendMessage bool) e
 	// Get all the Chremoas roles and build membership Sets
 	chremoasRoles, err := h.getRoles()
 	if err != nil {
-		msg := fmt.Sprintf("syncMembers: getRoles: %s", err.Error())
-		h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
+		msg := fmt.Sprintf("planMembers: getRoles: %s", err.Error())
+		h.sendFatal(msg, request)
 		sugar.Error(msg)
-		return err
+		return nil, err
 	}
 
 	h.sendDualMessage(
 		fmt.Sprintf("Got all Chremoas roles [%s]", time.Since(t)),
-		channelId,
-		sendMessage,
+		request,
 	)
 
 	t = time.Now()
 
+	resolver, err := h.newMembershipResolver()
+	if err != nil {
+		msg := fmt.Sprintf("planMembers: newMembershipResolver: %s", err.Error())
+		h.sendFatal(msg, request)
+		sugar.Error(msg)
+		return nil, err
+	}
+
 	for r := range chremoasRoles {
 		sugar.Debugf("Checking role: %s", chremoasRoles[r])
 		role, err := h.getRole(chremoasRoles[r])
 		if err != nil {
-			msg := fmt.Sprintf("syncMembers: getRole: %s: %s", chremoasRoles[r], err.Error())
-			h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
+			msg := fmt.Sprintf("planMembers: getRole: %s: %s", chremoasRoles[r], err.Error())
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
-			return err
+			return nil, err
 		}
 
 		if role["Sync"] == "0" || role["Sync"] == "false" {
 			continue
 		}
 
-		membership, err := h.getRoleMembership(chremoasRoles[r])
+		membership, err := resolver.membership(chremoasRoles[r])
 		if err != nil {
-			msg := fmt.Sprintf("syncMembers: getRoleMembership: %s", err.Error())
-			h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
+			msg := fmt.Sprintf("planMembers: getRoleMembership: %s", err.Error())
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
-			return err
+			return nil, err
 		}
+		plan.membership[chremoasRoles[r]] = membership
 
 		roleName, err := h.getRole(chremoasRoles[r])
 		if err != nil {
-			msg := fmt.Sprintf("syncMembers: getRole: %s", err.Error())
-			h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
+			msg := fmt.Sprintf("planMembers: getRole: %s", err.Error())
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
-			return err
+			return nil, err
 		}
 
-		//roleId := roleNameMap[roleName["Name"]]
+		// Go by the Discord role we created for this one, falling back to the name
+		// for roles from before we tracked Ids. Roles that aren't on Discord yet
+		// go in by name.
+		roleId := roleName["DiscordId"]
+		if _, ok := roleIdMap[roleId]; !ok {
+			roleId = roleName["Name"]
+			if id, ok := roleNameMap[roleName["Name"]]; ok {
+				roleId = id
+			}
+		}
+
+		managed.Add(roleId)
 
 		for m := range membership.Set {
 			sugar.Debugf("Key is: %s", m)
@@ -535,79 +640,95 @@ func (h *rolesHandler) syncMembers(channelId, userId string, sendMessage bool) e
 				if chremoasMemberships[m] == nil {
 					chremoasMemberships[m] = sets.NewStringSet()
 				}
-				chremoasMemberships[m].Add(roleName["Name"])
+				chremoasMemberships[m].Add(roleId)
 			}
 		}
 	}
 
 	h.sendDualMessage(
 		fmt.Sprintf("Got all role Memberships [%s]", time.Since(t)),
-		channelId,
-		sendMessage,
+		request,
 	)
 
-	t = time.Now()
-
 	for m := range chremoasMemberships {
 		if discordMemberships[m] == nil {
 			sugar.Debugf("not in discord: %v", m)
 			continue
 		}
 
+		target := request.targetRoles(discordMemberships[m], chremoasMemberships[m], managed)
+
 		// Get the list of memberships that are in chremoas but not discord (need to be added to discord)
-		diff := chremoasMemberships[m].Difference(discordMemberships[m])
-		diff2 := discordMemberships[m].Difference(chremoasMemberships[m])
+		diff := target.Difference(discordMemberships[m])
+		diff2 := discordMemberships[m].Difference(target)
 
 		if diff.Len() != 0 || diff2.Len() != 0 {
-			if !ignoreRole(idToNameMap[m]) {
-				for r := range chremoasMemberships[m].Set {
-					if _, ok := updateMembers[m]; !ok {
-						updateMembers[m] = sets.NewStringSet()
-					}
-					updateMembers[m].Add(roleNameMap[r])
-				}
+			if ignoreRole(idToNameMap[m]) {
+				continue
+			}
+
+			// Don't sync people who we don't want to mess with. Always put the Discord Server Owner here
+			// because we literally can't sync them no matter what.
+			noSync, _ := h.Store.IsNoSync(m)
+			if noSync {
+				sugar.Infof("Skipping noSync user: %s", m)
+				continue
+			}
+
+			change := &memberChange{userId: m, roles: target}
+			for id := range diff.Set {
+				change.add = append(change.add, plan.roleName(id))
+			}
+			for id := range diff2.Set {
+				change.remove = append(change.remove, plan.roleName(id))
 			}
+			sort.Strings(change.add)
+			sort.Strings(change.remove)
+			plan.changes = append(plan.changes, change)
 		}
 	}
 
+	sort.Slice(plan.changes, func(i, j int) bool { return plan.changes[i].userId < plan.changes[j].userId })
+
+	return plan, nil
+}
+
+// roleName returns the Discord name of a role Id in the plan. Anything that
+// isn't a known Id is already a name.
+func (p *memberPlan) roleName(id string) string {
+	if name, ok := p.roleNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+func (h *rolesHandler) syncMembers(request syncData, users *sets.StringSet) error {
+	plan, err := h.planMembers(request, users)
+	if err != nil {
+		return err
+	}
+
+	request.Job.update(func(j *rolesrv.SyncJob) { j.UsersToUpdate = int32(len(plan.changes)) })
+
+	t := time.Now()
+
 	// Apply the membership sets to discord overwriting anything that's there.
 	h.sendDualMessage(
-		fmt.Sprintf("Updating %d discord users", len(updateMembers)),
-		channelId,
-		sendMessage,
+		fmt.Sprintf("Updating %d discord users", len(plan.changes)),
+		request,
 	)
 
-	noSyncList := h.Redis.KeyName("members:no_sync")
-	sugar.Infof("noSyncList: %v", noSyncList)
-	for m := range updateMembers {
-		// Don't sync people who we don't want to mess with. Always put the Discord Server Owner here
-		// because we literally can't sync them no matter what.
-		noSync, _ := h.Redis.Client.SIsMember(noSyncList, m).Result()
-		if noSync {
-			sugar.Infof("Skipping noSync user: %s", m)
-			continue
-		}
-
-		ctx, _ := context.WithTimeout(context.Background(), time.Second*20)
-		_, err = clients.discord.UpdateMember(ctx, &discord.UpdateMemberRequest{
-			Operation: discord.MemberUpdateOperation_ADD_OR_UPDATE_ROLES,
-			UserId:    m,
-			RoleIds:   updateMembers[m].ToSlice(),
-		})
-		if err != nil {
-			msg := fmt.Sprintf("syncMembers: UpdateMember: %s", err.Error())
-			h.sendMessage(context.Background(), channelId, common.SendFatal(msg), true)
-			sugar.Error(msg)
-		}
-		sugar.Infof("Updating Discord User: %s", m)
+	if err := h.updateMembers(request, plan); err != nil {
+		return err
 	}
 
 	h.sendDualMessage(
 		fmt.Sprintf("Updated Discord Roles [%s]", time.Since(t)),
-		channelId,
-		sendMessage,
+		request,
 	)
 
+	syncedMembership.record(plan.membership, request.Scope)
+
 	return nil
 }
 
@@ -636,34 +757,47 @@ func (h *rolesHandler) GetRoleMembership(ctx context.Context, request *rolesrv.R
 	return nil
 }
 
+// getRoleMembership returns everyone in the role, including the members of
+// any roles that have it as a parent.
 func (h *rolesHandler) getRoleMembership(role string) (members *sets.StringSet, err error) {
+	resolver, err := h.newMembershipResolver()
+	if err != nil {
+		return sets.NewStringSet(), err
+	}
+
+	return resolver.membership(role)
+}
+
+// getDirectRoleMembership returns the members the role's own filters give it.
+func (h *rolesHandler) getDirectRoleMembership(role string) (members *sets.StringSet, err error) {
 	var filterASet = sets.NewStringSet()
 	var filterBSet = sets.NewStringSet()
 
-	roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", role))
-
-	r, err := h.Redis.Client.HGetAll(roleName).Result()
+	r, err := h.Store.GetRole(role)
 	if err != nil {
 		return filterASet, err
 	}
 
-	filterADesc := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", r["FilterA"]))
-	filterBDesc := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", r["FilterB"]))
+	if len(r["Expression"]) != 0 {
+		expression, err := parseExpression(r["Expression"])
+		if err != nil {
+			return filterASet, fmt.Errorf("Role `%s` has an invalid Expression: %s", role, err)
+		}
 
-	filterAMembers := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", r["FilterA"]))
-	filterBMembers := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", r["FilterB"]))
+		return expression.evaluate(h.getFilterMembers)
+	}
 
 	if r["FilterB"] == "wildcard" {
-		exists, err := h.Redis.Client.Exists(filterADesc).Result()
+		exists, err := h.Store.FilterExists(r["FilterA"])
 		if err != nil {
 			return filterASet, err
 		}
 
-		if exists == 0 {
+		if !exists {
 			return filterASet, fmt.Errorf("Filter `%s` doesn't exists.", r["FilterA"])
 		}
 
-		filterA, err := h.Redis.Client.SMembers(filterAMembers).Result()
+		filterA, err := h.Store.GetFilterMembers(r["FilterA"])
 		if err != nil {
 			return filterASet, err
 		}
@@ -673,16 +807,16 @@ func (h *rolesHandler) getRoleMembership(role string) (members *sets.StringSet,
 	}
 
 	if r["FilterA"] == "wildcard" {
-		exists, err := h.Redis.Client.Exists(filterBDesc).Result()
+		exists, err := h.Store.FilterExists(r["FilterB"])
 		if err != nil {
 			return filterASet, err
 		}
 
-		if exists == 0 {
+		if !exists {
 			return filterASet, fmt.Errorf("Filter `%s` doesn't exists.", r["FilterB"])
 		}
 
-		filterB, err := h.Redis.Client.SMembers(filterBMembers).Result()
+		filterB, err := h.Store.GetFilterMembers(r["FilterB"])
 		if err != nil {
 			return filterASet, err
 		}
@@ -691,59 +825,112 @@ func (h *rolesHandler) getRoleMembership(role string) (members *sets.StringSet,
 		return filterBSet, nil
 	}
 
-	filterInter, err := h.Redis.Client.SInter(filterAMembers, filterBMembers).Result()
+	filterA, err := h.Store.GetFilterMembers(r["FilterA"])
+	if err != nil {
+		return filterASet, err
+	}
+
+	filterB, err := h.Store.GetFilterMembers(r["FilterB"])
 	if err != nil {
 		return filterASet, err
 	}
 
-	filterASet.FromSlice(filterInter)
-	return filterASet, nil
+	filterASet.FromSlice(filterA)
+	filterBSet.FromSlice(filterB)
+	return filterASet.Intersection(filterBSet), nil
+}
+
+// getFilterMembers returns the members of a filter that has to exist.
+func (h *rolesHandler) getFilterMembers(filter string) (*sets.StringSet, error) {
+	exists, err := h.Store.FilterExists(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("Filter `%s` doesn't exists.", filter)
+	}
+
+	members, err := h.Store.GetFilterMembers(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	set := sets.NewStringSet()
+	set.FromSlice(members)
+	return set, nil
+}
+
+// rolePlan is what syncRoles would do to the Discord roles. Chremoas roles
+// are matched to Discord roles by the DiscordId saved when the sync created
+// them, or by name for roles from before Ids were saved (link holds the Ids
+// that need saving for those). The gateway only finds roles by name, so a
+// Discord role sharing its name with another is never deleted or edited;
+// skip holds those names.
+type rolePlan struct {
+	create []*roleCreate
+	delete []string
+	edit   []*roleEdit
+	link   map[string]string
+	skip   []string
+}
+
+type roleCreate struct {
+	shortName string
+	name      string
+}
+
+// roleEdit is a change to an existing Discord role. request.Name is the
+// role's current name on Discord, which is how the gateway finds it, and
+// rename is the name it should have if that's different.
+type roleEdit struct {
+	request *discord.EditRoleRequest
+	rename  string
+	changes []*rolesrv.FieldChange
 }
 
-func (h *rolesHandler) syncRoles(channelId, userId string, sendMessage bool) error {
+// planRoles works out which Discord roles need creating, deleting or editing
+// without changing anything.
+func (h *rolesHandler) planRoles(request syncData) (*rolePlan, error) {
 	ctx := context.Background()
-	var matchDiscordError = regexp.MustCompile(`^The role '.*' already exists$`)
-	chremoasRoleSet := sets.NewStringSet()
-	discordRoleSet := sets.NewStringSet()
 	sugar := h.Logger.Sugar()
-	var chremoasRoleData = make(map[string]map[string]string)
+	var chremoasRoleData []map[string]string
+	var discordById = make(map[string]*discord.Role)
+	var discordByName = make(map[string]*discord.Role)
 
 	chremoasRoles, err := h.getRoles()
 	if err != nil {
-		msg := fmt.Sprintf("syncRoles: h.getRoles(): %s", err.Error())
-		h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+		msg := fmt.Sprintf("planRoles: h.getRoles(): %s", err.Error())
+		h.sendFatal(msg, request)
 		sugar.Error(msg)
-		return err
+		return nil, err
 	}
 
+	sort.Strings(chremoasRoles)
+
 	for role := range chremoasRoles {
-		roleName := h.Redis.KeyName(fmt.Sprintf("role:%s", chremoasRoles[role]))
-		c, err := h.Redis.Client.HGetAll(roleName).Result()
+		c, err := h.Store.GetRole(chremoasRoles[role])
 
 		if err != nil {
-			msg := fmt.Sprintf("syncRoles: HGetAll(): %s", err.Error())
-			h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+			msg := fmt.Sprintf("planRoles: GetRole(): %s", err.Error())
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
-			return err
+			return nil, err
 		}
 
 		sugar.Debugf("Checking %s: %s", c["Name"], c["Sync"])
 		if c["Sync"] == "1" || c["Sync"] == "true" {
-			chremoasRoleSet.Add(c["Name"])
-
-			if _, ok := chremoasRoleData[c["Name"]]; !ok {
-				chremoasRoleData[c["Name"]] = make(map[string]string)
-			}
-			chremoasRoleData[c["Name"]] = c
+			c["ShortName"] = chremoasRoles[role]
+			chremoasRoleData = append(chremoasRoleData, c)
 		}
 	}
 
 	discordRoles, err := clients.discord.GetAllRoles(ctx, &discord.GuildObjectRequest{})
 	if err != nil {
-		msg := fmt.Sprintf("syncRoles: GetAllRoles: %s", err.Error())
-		h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+		msg := fmt.Sprintf("planRoles: GetAllRoles: %s", err.Error())
+		h.sendFatal(msg, request)
 		sugar.Error(msg)
-		return err
+		return nil, err
 	}
 
 	ignoreSet := sets.NewStringSet()
@@ -753,46 +940,185 @@ func (h *rolesHandler) syncRoles(channelId, userId string, sendMessage bool) err
 		ignoreSet.Add(ignoredRoles[i])
 	}
 
+	// Counted across every role, since that's what the gateway searches
+	nameCount := make(map[string]int)
+	for role := range discordRoles.Roles {
+		nameCount[discordRoles.Roles[role].Name]++
+	}
+
 	for role := range discordRoles.Roles {
 		if !ignoreSet.Contains(discordRoles.Roles[role].Name) {
-			discordRoleSet.Add(discordRoles.Roles[role].Name)
+			discordById[discordRoles.Roles[role].Id] = discordRoles.Roles[role]
+			discordByName[discordRoles.Roles[role].Name] = discordRoles.Roles[role]
+		}
+	}
+
+	plan := &rolePlan{link: make(map[string]string)}
+	matched := make(map[string]*discord.Role)
+	claimed := sets.NewStringSet()
+
+	// Ids first so a renamed role can't be claimed by name by another role
+	for _, c := range chremoasRoleData {
+		if d, ok := discordById[c["DiscordId"]]; ok {
+			matched[c["ShortName"]] = d
+			claimed.Add(d.Id)
+		}
+	}
+
+	for _, c := range chremoasRoleData {
+		if _, ok := matched[c["ShortName"]]; ok {
+			continue
+		}
+
+		if d, ok := discordByName[c["Name"]]; ok && !claimed.Contains(d.Id) {
+			matched[c["ShortName"]] = d
+			claimed.Add(d.Id)
+			plan.link[c["ShortName"]] = d.Id
+			continue
+		}
+
+		plan.create = append(plan.create, &roleCreate{shortName: c["ShortName"], name: c["Name"]})
+	}
+
+	for id, d := range discordById {
+		switch {
+		case claimed.Contains(id):
+		case nameCount[d.Name] > 1:
+			plan.skip = append(plan.skip, d.Name)
+		default:
+			plan.delete = append(plan.delete, d.Name)
+		}
+	}
+
+	sort.Strings(plan.delete)
+
+	sugar.Debugf("toAdd: %v", plan.create)
+	sugar.Debugf("toDelete: %v", plan.delete)
+	sugar.Debugf("toUpdate: %v", matched)
+
+	for _, c := range chremoasRoleData {
+		d, ok := matched[c["ShortName"]]
+		if !ok {
+			continue
+		}
+
+		color, _ := strconv.ParseInt(c["Color"], 10, 64)
+		perm, _ := strconv.ParseInt(c["Permissions"], 10, 64)
+		position, _ := strconv.ParseInt(c["Position"], 10, 64)
+		hoist, _ := strconv.ParseBool(c["Hoist"])
+		mention, _ := strconv.ParseBool(c["Mentionable"])
+		managed, _ := strconv.ParseBool(c["Managed"])
+
+		editRequest := &discord.EditRoleRequest{
+			Name:     d.Name,
+			Color:    color,
+			Perm:     perm,
+			Position: position,
+			Hoist:    hoist,
+			Mention:  mention,
+			Managed:  managed,
+		}
+
+		edit := &roleEdit{request: editRequest, changes: roleChanges(d, editRequest)}
+		if d.Name != c["Name"] {
+			edit.rename = c["Name"]
+			edit.changes = append([]*rolesrv.FieldChange{{Field: "Name", From: d.Name, To: c["Name"]}}, edit.changes...)
+		}
+
+		switch {
+		case len(edit.changes) == 0:
+		case nameCount[d.Name] > 1:
+			plan.skip = append(plan.skip, d.Name)
+		default:
+			plan.edit = append(plan.edit, edit)
+		}
+	}
+
+	sort.Strings(plan.skip)
+
+	return plan, nil
+}
+
+// roleChanges lists the fields an EditRole would change on a Discord role.
+func roleChanges(current *discord.Role, edit *discord.EditRoleRequest) []*rolesrv.FieldChange {
+	var changes []*rolesrv.FieldChange
+
+	change := func(field string, from, to interface{}) {
+		if from != to {
+			changes = append(changes, &rolesrv.FieldChange{Field: field, From: fmt.Sprint(from), To: fmt.Sprint(to)})
 		}
 	}
 
-	toAdd := chremoasRoleSet.Difference(discordRoleSet)
-	toDelete := discordRoleSet.Difference(chremoasRoleSet)
-	toUpdate := discordRoleSet.Intersection(chremoasRoleSet)
+	change("Color", int64(current.Color), edit.Color)
+	change("Permissions", int64(current.Permissions), edit.Perm)
+	change("Position", int64(current.Position), edit.Position)
+	change("Hoist", current.Hoist, edit.Hoist)
+	change("Mentionable", current.Mentionable, edit.Mention)
+	change("Managed", current.Managed, edit.Managed)
+
+	return changes
+}
+
+func (h *rolesHandler) syncRoles(request syncData) error {
+	ctx := context.Background()
+	var matchDiscordError = regexp.MustCompile(`^The role '.*' already exists$`)
+	sugar := h.Logger.Sugar()
+
+	plan, err := h.planRoles(request)
+	if err != nil {
+		return err
+	}
+
+	if err := request.Lease.check(); err != nil {
+		return err
+	}
+
+	for shortName, id := range plan.link {
+		h.saveDiscordId(shortName, id)
+	}
+
+	for _, name := range plan.skip {
+		h.sendDualMessage(
+			fmt.Sprintf("More than one Discord role is named '%s', leaving them alone until one is renamed by hand", name),
+			request,
+		)
+	}
 
-	sugar.Debugf("toAdd: %v", toAdd)
-	sugar.Debugf("toDelete: %v", toDelete)
-	sugar.Debugf("toUpdate: %v", toUpdate)
+	for _, r := range plan.create {
+		if err := request.Lease.check(); err != nil {
+			return err
+		}
 
-	for r := range toAdd.Set {
-		_, err := clients.discord.CreateRole(ctx, &discord.CreateRoleRequest{Name: r})
+		response, err := clients.discord.CreateRole(ctx, &discord.CreateRoleRequest{Name: r.name})
 
 		if err != nil {
 			if matchDiscordError.MatchString(err.Error()) {
 				// The role list was cached most likely so we'll pretend we didn't try
 				// to create it just now. -brian
-				sugar.Debugf("syncRoles added: %s", r)
+				sugar.Debugf("syncRoles added: %s", r.name)
 				continue
 			} else {
-				msg := fmt.Sprintf("syncRoles: CreateRole() attempting to create '%s': %s", r, err.Error())
-				h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+				msg := fmt.Sprintf("syncRoles: CreateRole() attempting to create '%s': %s", r.name, err.Error())
+				h.sendFatal(msg, request)
 				sugar.Error(msg)
 				return err
 			}
 		}
 
-		sugar.Debugf("syncRoles added: %s", r)
+		h.saveDiscordId(r.shortName, response.RoleId)
+		sugar.Debugf("syncRoles added: %s", r.name)
 	}
 
-	for r := range toDelete.Set {
+	for _, r := range plan.delete {
+		if err := request.Lease.check(); err != nil {
+			return err
+		}
+
 		_, err := clients.discord.DeleteRole(ctx, &discord.DeleteRoleRequest{Name: r})
 
 		if err != nil {
 			msg := fmt.Sprintf("syncRoles: DeleteRole() Error Deleting '%s': %s", r, err.Error())
-			h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
 			return err
 		}
@@ -800,68 +1126,72 @@ func (h *rolesHandler) syncRoles(channelId, userId string, sendMessage bool) err
 		sugar.Debugf("syncRoles removed: %s", r)
 	}
 
-	for r := range toUpdate.Set {
-		color, _ := strconv.ParseInt(chremoasRoleData[r]["Color"], 10, 64)
-		perm, _ := strconv.ParseInt(chremoasRoleData[r]["Permissions"], 10, 64)
-		position, _ := strconv.ParseInt(chremoasRoleData[r]["Position"], 10, 64)
-		hoist, _ := strconv.ParseBool(chremoasRoleData[r]["Hoist"])
-		mention, _ := strconv.ParseBool(chremoasRoleData[r]["Mentionable"])
-		managed, _ := strconv.ParseBool(chremoasRoleData[r]["Managed"])
+	for _, r := range plan.edit {
+		// The gateway finds roles by name and has no way to change one, so a
+		// rename has to be done by hand on Discord until it can edit by Id.
+		// The role stays matched by Id until then so nobody loses it in the
+		// meantime.
+		if r.rename != "" {
+			h.sendDualMessage(
+				fmt.Sprintf("Discord role '%s' needs renaming to '%s' by hand", r.request.Name, r.rename),
+				request,
+			)
 
-		editRequest := &discord.EditRoleRequest{
-			Name:     chremoasRoleData[r]["Name"],
-			Color:    color,
-			Perm:     perm,
-			Position: position,
-			Hoist:    hoist,
-			Mention:  mention,
-			Managed:  managed,
+			if len(r.changes) == 1 {
+				continue
+			}
+		}
+
+		if err := request.Lease.check(); err != nil {
+			return err
 		}
 
 		longCtx, _ := context.WithTimeout(ctx, time.Minute*5)
-		_, err := clients.discord.EditRole(longCtx, editRequest)
+		_, err := clients.discord.EditRole(longCtx, r.request)
 		if err != nil {
 			msg := fmt.Sprintf("syncRoles: EditRole(): %s", err.Error())
-			h.sendMessage(ctx, channelId, common.SendFatal(msg), true)
+			h.sendFatal(msg, request)
 			sugar.Error(msg)
 			return err
 		}
 
-		sugar.Debugf("syncRoles updated: %s", r)
+		sugar.Debugf("syncRoles updated: %s", r.request.Name)
 	}
 
 	return nil
 }
 
+// saveDiscordId records which Discord role a Chremoas role is synced to.
+func (h *rolesHandler) saveDiscordId(shortName, id string) {
+	err := h.Store.UpdateRole(shortName, map[string]string{"DiscordId": id}, nil)
+	if err != nil {
+		h.Logger.Sugar().Errorf("saveDiscordId: %s: %s", shortName, err)
+	}
+}
+
 //
 // Filter related stuff
 //
 
 func (h *rolesHandler) GetFilters(ctx context.Context, request *rolesrv.NilMessage, response *rolesrv.FilterList) error {
-	filters, err := h.Redis.Client.Keys(h.Redis.KeyName("filter_description:*")).Result()
+	filters, err := h.Store.GetFilters()
 
 	if err != nil {
 		return err
 	}
 
-	for filter := range filters {
-		filterDescription, err := h.Redis.Client.Get(filters[filter]).Result()
-
-		if err != nil {
-			return err
-		}
-
-		filterName := strings.Split(filters[filter], ":")
-
+	for filterName, filterDescription := range filters {
 		response.FilterList = append(response.FilterList,
-			&rolesrv.Filter{Name: filterName[len(filterName)-1], Description: filterDescription})
+			&rolesrv.Filter{Name: filterName, Description: filterDescription})
 	}
 
 	return nil
 }
 
 func (h *rolesHandler) AddFilter(ctx context.Context, request *rolesrv.Filter, response *rolesrv.NilMessage) error {
-	filterName := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.Name))
+	if err := h.authorize(ctx, "AddFilter", roleAdmins, sigAdmins); err != nil {
+		return err
+	}
 
 	// Type and Name are required so let's check for those
 	if len(request.Name) == 0 {
@@ -872,62 +1202,57 @@ func (h *rolesHandler) AddFilter(ctx context.Context, request *rolesrv.Filter, r
 		return errors.New("Description is required.")
 	}
 
-	exists, err := h.Redis.Client.Exists(filterName).Result()
-
-	if err != nil {
-		return err
-	}
+	err := h.Store.CreateFilter(request.Name, request.Description)
 
-	if exists == 1 {
+	if err == storage.ErrFilterExists {
 		return fmt.Errorf("Filter `%s` already exists.", request.Name)
 	}
 
-	_, err = h.Redis.Client.Set(filterName, request.Description, 0).Result()
-
 	if err != nil {
 		return err
 	}
 
+	h.audit(actingUser(ctx), "AddFilter", request.Name, nil, request)
+
 	response = &rolesrv.NilMessage{}
 
 	return nil
 }
 
 func (h *rolesHandler) RemoveFilter(ctx context.Context, request *rolesrv.Filter, response *rolesrv.NilMessage) error {
-	filterName := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.Name))
-	filterMembers := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", request.Name))
-
-	exists, err := h.Redis.Client.Exists(filterName).Result()
-
-	if err != nil {
+	if err := h.authorize(ctx, "RemoveFilter", roleAdmins, sigAdmins); err != nil {
 		return err
 	}
 
-	if exists == 0 {
-		return fmt.Errorf("Filter `%s` doesn't exists.", request.Name)
-	}
-
-	members, err := h.Redis.Client.SMembers(filterMembers).Result()
+	err := h.Store.RemoveFilter(request.Name)
 
-	if len(members) > 0 {
+	switch err {
+	case nil:
+	case storage.ErrFilterNotFound:
+		return fmt.Errorf("Filter `%s` doesn't exists.", request.Name)
+	case storage.ErrFilterNotEmpty:
 		return fmt.Errorf("Filter `%s` not empty.", request.Name)
-	}
-
-	_, err = h.Redis.Client.Del(filterName).Result()
-
-	if err != nil {
+	default:
 		return err
 	}
 
+	h.audit(actingUser(ctx), "RemoveFilter", request.Name, request, nil)
+
 	response = &rolesrv.NilMessage{}
 	return nil
 }
 
 func (h *rolesHandler) GetMembers(ctx context.Context, request *rolesrv.Filter, response *rolesrv.MemberList) error {
 	var memberlist []string
-	filterName := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", request.Name))
+	var expiries []int64
+
+	filters, err := h.Store.GetFilterMembers(request.Name)
+
+	if err != nil {
+		return err
+	}
 
-	filters, err := h.Redis.Client.SMembers(filterName).Result()
+	memberExpiries, err := h.Store.GetMemberExpiries(request.Name)
 
 	if err != nil {
 		return err
@@ -936,61 +1261,107 @@ func (h *rolesHandler) GetMembers(ctx context.Context, request *rolesrv.Filter,
 	for filter := range filters {
 		if len(filters[filter]) > 0 {
 			memberlist = append(memberlist, filters[filter])
+			expiries = append(expiries, memberExpiries[filters[filter]])
 		}
 	}
 
 	response.Members = memberlist
+	response.ExpiresAt = expiries
 	return nil
 }
 
 func (h *rolesHandler) AddMembers(ctx context.Context, request *rolesrv.Members, response *rolesrv.NilMessage) error {
-	filterName := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", request.Filter))
-	filterDesc := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.Filter))
+	selfJoin, err := h.authorizeMembers(ctx, "AddMembers", request, true)
+	if err != nil {
+		return err
+	}
+
+	if len(request.ExpiresAt) != 0 && len(request.ExpiresAt) != len(request.Name) {
+		return errors.New("ExpiresAt needs one entry per member.")
+	}
 
-	exists, err := h.Redis.Client.Exists(filterDesc).Result()
+	now := time.Now().Unix()
+	for e := range request.ExpiresAt {
+		if request.ExpiresAt[e] != 0 && request.ExpiresAt[e] <= now {
+			return fmt.Errorf("Expiry for `%s` is in the past.", request.Name[e])
+		}
+	}
+
+	exists, err := h.Store.FilterExists(request.Filter)
 
 	if err != nil {
 		return err
 	}
 
-	if exists == 0 {
+	if !exists {
 		return fmt.Errorf("Filter `%s` doesn't exists.", request.Filter)
 	}
 
-	for member := range request.Name {
-		_, err = h.Redis.Client.SAdd(filterName, request.Name[member]).Result()
+	// Going around JoinSIG doesn't get you past the capacity
+	if selfJoin {
+		unlock, err := h.lockAdmissions(request.Filter)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := h.checkFilterRoom(request.Filter); err != nil {
+			return err
+		}
+	}
+
+	err = h.Store.AddFilterMembers(request.Filter, request.Name)
+
+	if err != nil {
+		return err
+	}
+
+	// Adding someone without an expiry makes them permanent, even if they
+	// were only there temporarily before.
+	expiries := make(map[string]int64, len(request.Name))
+	for m := range request.Name {
+		expiries[request.Name[m]] = 0
+		if len(request.ExpiresAt) != 0 {
+			expiries[request.Name[m]] = request.ExpiresAt[m]
+		}
 	}
 
+	err = h.Store.SetMemberExpiries(request.Filter, expiries)
+
 	if err != nil {
 		return err
 	}
 
+	h.audit(actingUser(ctx), "AddMembers", request.Filter, nil, expiries)
+
 	response = &rolesrv.NilMessage{}
 	return nil
 }
 
 func (h *rolesHandler) RemoveMembers(ctx context.Context, request *rolesrv.Members, response *rolesrv.NilMessage) error {
-	filterName := h.Redis.KeyName(fmt.Sprintf("filter_members:%s", request.Filter))
-	filterDesc := h.Redis.KeyName(fmt.Sprintf("filter_description:%s", request.Filter))
+	if _, err := h.authorizeMembers(ctx, "RemoveMembers", request, false); err != nil {
+		return err
+	}
 
-	exists, err := h.Redis.Client.Exists(filterDesc).Result()
+	exists, err := h.Store.FilterExists(request.Filter)
 
 	if err != nil {
 		return err
 	}
 
-	if exists == 0 {
+	if !exists {
 		return fmt.Errorf("Filter `%s` doesn't exists.", request.Filter)
 	}
 
-	for member := range request.Name {
-		_, err = h.Redis.Client.SRem(filterName, request.Name[member]).Result()
-	}
+	err = h.Store.RemoveFilterMembers(request.Filter, request.Name)
 
 	if err != nil {
 		return err
 	}
 
+	h.audit(actingUser(ctx), "RemoveMembers", request.Filter, request.Name, nil)
+	promotions.add(request.Filter)
+
 	response = &rolesrv.NilMessage{}
 	return nil
 }
@@ -1041,42 +1412,124 @@ func (h *rolesHandler) GetDiscordUserList(ctx context.Context, request *rolesrv.
 	return nil
 }
 
-func (h *rolesHandler) SyncToChatService(ctx context.Context, request *rolesrv.SyncRequest, response *rolesrv.NilMessage) error {
-	syncControl <- syncData{ChannelId: request.ChannelId, UserId: request.UserId, SendMessage: request.SendMessage}
+func (h *rolesHandler) SyncToChatService(ctx context.Context, request *rolesrv.SyncRequest, response *rolesrv.SyncJob) error {
+	if err := h.authorizeSync(ctx, request); err != nil {
+		return err
+	}
+
+	job, alreadyQueued := syncControl.add(syncRequester{
+		ChannelId:   request.ChannelId,
+		UserId:      request.UserId,
+		SendMessage: request.SendMessage,
+	}, syncRequestOptions(request))
+
+	*response = *job.snapshot()
+	response.AlreadyQueued = alreadyQueued
+	return nil
+}
+
+func (h *rolesHandler) GetSyncJob(ctx context.Context, request *rolesrv.SyncJobRequest, response *rolesrv.SyncJob) error {
+	job, err := syncJobs.get(request.Id)
+	if err != nil {
+		return err
+	}
+
+	if job == nil {
+		return fmt.Errorf("Sync job `%s` doesn't exists.", request.Id)
+	}
+
+	*response = *job
 	return nil
 }
 
-func (h *rolesHandler) sendDualMessage(msg, channelId string, sendMessage bool) {
+func (h *rolesHandler) GetSyncHistory(ctx context.Context, request *rolesrv.NilMessage, response *rolesrv.SyncJobList) error {
+	jobs, err := syncJobs.history()
+	if err != nil {
+		return err
+	}
+
+	response.Jobs = jobs
+	return nil
+}
+
+func (h *rolesHandler) sendDualMessage(msg string, request syncData) {
 	ctx := context.Background()
 	sugar := h.Logger.Sugar()
 
 	sugar.Info(msg)
-	h.sendMessage(ctx, channelId, common.SendSuccess(msg), sendMessage)
+	for _, r := range request.Requesters {
+		h.sendMessage(ctx, r.ChannelId, common.SendSuccess(msg), r.SendMessage)
+	}
+}
+
+// sendFatal tells everyone waiting on the sync that something went wrong,
+// whether they asked for progress messages or not.
+func (h *rolesHandler) sendFatal(msg string, request syncData) {
+	ctx := context.Background()
+
+	for _, r := range request.Requesters {
+		h.sendMessage(ctx, r.ChannelId, common.SendFatal(msg), r.ChannelId != "")
+	}
 }
 
 func (h *rolesHandler) syncThread() {
 	for {
-		request := <-syncControl
+		request := syncControl.next()
+
+		// Only one replica syncs at a time
+		request.Lease = syncLeader.acquire()
+		request.Job.start()
 
 		t1 := time.Now()
 
-		h.sendDualMessage("Starting Role Sync", request.ChannelId, request.SendMessage)
+		var users *sets.StringSet
+		if request.Scope != nil {
+			var err error
+			users, err = h.scopeUsers(request.Scope)
+			if err != nil {
+				msg := fmt.Sprintf("syncThread: scopeUsers: %s", err.Error())
+				h.sendFatal(msg, request)
+				h.Logger.Sugar().Error(msg)
+				request.Job.addError("scopeUsers: %s", err)
+				request.Lease.release()
+				request.Job.finish()
+				h.sendSummary(request)
+				continue
+			}
 
-		h.syncRoles(request.ChannelId, request.UserId, request.SendMessage)
+			if users == nil {
+				h.sendDualMessage("Some of these roles haven't been synced yet, running a full sync", request)
+			}
+		}
+
+		if users == nil {
+			h.sendDualMessage("Starting Role Sync", request)
+
+			if err := h.syncRoles(request); err != nil {
+				request.Job.addError("syncRoles: %s", err)
+			}
 
-		msg := fmt.Sprintf("Completed Role Sync [%s]", time.Since(t1))
-		h.sendDualMessage(msg, request.ChannelId, request.SendMessage)
+			msg := fmt.Sprintf("Completed Role Sync [%s]", time.Since(t1))
+			h.sendDualMessage(msg, request)
+		}
 
 		t2 := time.Now()
-		h.sendDualMessage("Starting Member Sync", request.ChannelId, request.SendMessage)
+		h.sendDualMessage("Starting Member Sync", request)
 
-		h.syncMembers(request.ChannelId, request.UserId, request.SendMessage)
+		if err := h.syncMembers(request, users); err != nil {
+			request.Job.addError("syncMembers: %s", err)
+		}
 
-		msg = fmt.Sprintf("Completed Member Sync [%s]", time.Since(t2))
-		h.sendDualMessage(msg, request.ChannelId, request.SendMessage)
+		msg := fmt.Sprintf("Completed Member Sync [%s]", time.Since(t2))
+		h.sendDualMessage(msg, request)
 
 		msg = fmt.Sprintf("Completed All Syncing [%s]", time.Since(t1))
-		h.sendDualMessage(msg, request.ChannelId, request.SendMessage)
+		h.sendDualMessage(msg, request)
+
+		request.Lease.release()
+		request.Job.finish()
+		h.auditSync(request)
+		h.sendSummary(request)
 	}
 }
 
@@ -1086,8 +1539,13 @@ func (h *rolesHandler) ListUserRoles(ctx context.Context, request *rolesrv.ListU
 		return err
 	}
 
+	resolver, err := h.newMembershipResolver()
+	if err != nil {
+		return err
+	}
+
 	for role := range roles {
-		r, err := h.getRoleMembership(roles[role])
+		r, err := resolver.membership(roles[role])
 		if err != nil {
 			return err
 		}
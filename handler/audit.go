@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	"github.com/chremoas/services-common/sets"
+	"golang.org/x/net/context"
+)
+
+// defaultAuditLimit is how many entries GetAuditLog returns if not told.
+const defaultAuditLimit = 100
+
+// audit records a change in the audit log. before and after are stored as
+// JSON, nil as nothing. The change has already happened by the time this is
+// called, so failing to record it is logged rather than returned.
+func (h *rolesHandler) audit(actor, method, target string, before, after interface{}) {
+	entry := &storage.AuditEntry{
+		Time:   time.Now().Unix(),
+		Actor:  actor,
+		Method: method,
+		Target: target,
+		Before: auditValue(before),
+		After:  auditValue(after),
+	}
+
+	if err := h.Store.AppendAudit(entry); err != nil {
+		h.Logger.Sugar().Errorf("audit: %s %s by %s: %s", method, target, actor, err)
+	}
+}
+
+// auditSync records a finished sync run against its job, by whoever asked for
+// it. Scheduled and expiry syncs have no requesting user.
+func (h *rolesHandler) auditSync(request syncData) {
+	actors := sets.NewStringSet()
+	for _, r := range request.Requesters {
+		actors.Add(r.UserId)
+	}
+
+	users := actors.ToSlice()
+	sort.Strings(users)
+
+	job := request.Job.snapshot()
+	h.audit(strings.Join(users, ","), "Sync", job.Id, nil, map[string]interface{}{
+		"State":         job.State.String(),
+		"Mode":          request.Mode.String(),
+		"UsersToUpdate": job.UsersToUpdate,
+		"UsersUpdated":  job.UsersUpdated,
+		"Errors":        job.Errors,
+	})
+}
+
+func auditValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func (h *rolesHandler) GetAuditLog(ctx context.Context, request *rolesrv.AuditQuery, response *rolesrv.AuditLog) error {
+	if err := h.authorize(ctx, "GetAuditLog", roleAdmins, sigAdmins); err != nil {
+		return err
+	}
+
+	limit := int(request.Limit)
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	entries, err := h.Store.GetAudit(storage.AuditQuery{
+		Actor:  request.Actor,
+		Target: request.Target,
+		From:   request.From,
+		To:     request.To,
+		Limit:  limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		response.Entries = append(response.Entries, &rolesrv.AuditEntry{
+			Id:     e.Id,
+			Time:   e.Time,
+			Actor:  e.Actor,
+			Method: e.Method,
+			Target: e.Target,
+			Before: e.Before,
+			After:  e.After,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	permsrv "github.com/chremoas/perms-srv/proto"
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	microerrors "github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/metadata"
+	"golang.org/x/net/context"
+)
+
+// Every RPC that changes something checks the permissions of the user the
+// caller says it's acting for (rolesrv.UserMetadataKey) with perms-srv, so
+// going around the client package doesn't get around them. Anything it
+// refuses comes back as a go-micro Forbidden error. roles.enforcePermissions
+// set to false turns the checks off, for callers that haven't started sending
+// the user yet.
+
+const (
+	roleAdmins = "role_admins"
+	sigAdmins  = "sig_admins"
+)
+
+// serviceName is the id on the errors this service returns.
+var serviceName string
+
+// enforcePermissions is roles.enforcePermissions.
+var enforcePermissions bool
+
+// actingUser returns the user the request is on behalf of, or "" if the
+// caller didn't say.
+func actingUser(ctx context.Context) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	// Transports don't agree on the case of header names
+	for key, value := range md {
+		if strings.EqualFold(key, rolesrv.UserMetadataKey) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// isSet reads a bool role field, which older data has as "true" rather
+// than "1".
+func isSet(value string) bool {
+	return value == "1" || value == "true"
+}
+
+// adminsFor is the permission needed to manage a role or SIG.
+func adminsFor(sig bool) string {
+	if sig {
+		return sigAdmins
+	}
+	return roleAdmins
+}
+
+// authorize returns nil if the acting user has any one of permissions.
+func (h *rolesHandler) authorize(ctx context.Context, method string, permissions ...string) error {
+	if !enforcePermissions {
+		return nil
+	}
+
+	user := actingUser(ctx)
+	if user == "" {
+		return microerrors.Forbidden(serviceName, "%s: no %s in the request metadata", method, rolesrv.UserMetadataKey)
+	}
+
+	for _, permission := range permissions {
+		canPerform, err := clients.perms.Perform(ctx, &permsrv.PermissionsRequest{
+			User:            user,
+			PermissionsList: []string{permission},
+		})
+		if err != nil {
+			return err
+		}
+
+		if canPerform.CanPerform {
+			return nil
+		}
+	}
+
+	return microerrors.Forbidden(serviceName, "%s: user %s needs %s", method, user, strings.Join(permissions, " or "))
+}
+
+// isForbidden is true for the errors authorize refuses with.
+func isForbidden(err error) bool {
+	e, ok := err.(*microerrors.Error)
+	return ok && e.Code == http.StatusForbidden
+}
+
+// authorizeRole checks the acting user can manage an existing role, which
+// depends on whether it's a SIG. A role that doesn't exist needs role_admins;
+// the RPC will then say it doesn't exist.
+func (h *rolesHandler) authorizeRole(ctx context.Context, method, name string) error {
+	if !enforcePermissions {
+		return nil
+	}
+
+	role, err := h.Store.GetRole(name)
+	if err != nil && err != storage.ErrRoleNotFound {
+		return err
+	}
+
+	return h.authorize(ctx, method, adminsFor(isSet(role["Sig"])))
+}
+
+// authorizeRoleUpdate lets a role's managers change how it looks; anything
+// else needs an admin.
+func (h *rolesHandler) authorizeRoleUpdate(ctx context.Context, method, name string, keys []string) error {
+	if !enforcePermissions {
+		return nil
+	}
+
+	looks := true
+	for _, key := range keys {
+		looks = looks && validListItem(key, managerKeys)
+	}
+
+	user := actingUser(ctx)
+	if user != "" && looks {
+		role, err := h.Store.GetRole(name)
+		if err != nil && err != storage.ErrRoleNotFound {
+			return err
+		}
+
+		manager, err := h.isManager(user, role)
+		if err != nil {
+			return err
+		}
+
+		if manager {
+			return nil
+		}
+	}
+
+	return h.authorizeRole(ctx, method, name)
+}
+
+// authorizeMembers lets anyone join a joinable SIG or leave any SIG by
+// changing their own membership of its filter, and a role's managers change
+// the members of its FilterB (see managesFilter). Everything else needs an
+// admin. It returns true when the change is someone joining on their own,
+// which still has to fit the SIG's capacity.
+func (h *rolesHandler) authorizeMembers(ctx context.Context, method string, request *rolesrv.Members, join bool) (bool, error) {
+	if !enforcePermissions {
+		return false, nil
+	}
+
+	user := actingUser(ctx)
+	if user != "" && len(request.Name) == 1 && request.Name[0] == user && len(request.ExpiresAt) == 0 {
+		selfService, err := h.selfServiceFilter(request.Filter, join)
+		if err != nil {
+			return false, err
+		}
+
+		if selfService {
+			return join, nil
+		}
+	}
+
+	if user != "" {
+		manager, err := h.managesFilter(user, request.Filter)
+		if err != nil {
+			return false, err
+		}
+
+		if manager {
+			return false, nil
+		}
+	}
+
+	return false, h.authorize(ctx, method, roleAdmins, sigAdmins)
+}
+
+// selfServiceFilter is true if filter is what decides membership of a SIG a
+// user may leave on their own. To join, every SIG it decides has to be
+// Joinable, or sharing a filter would get you into one that isn't, or past
+// its JoinApproval.
+func (h *rolesHandler) selfServiceFilter(filter string, join bool) (bool, error) {
+	roles, err := h.getRoles()
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for r := range roles {
+		role, err := h.getRole(roles[r])
+		if err != nil {
+			return false, err
+		}
+
+		if !isSet(role["Sig"]) || role["FilterB"] != filter {
+			continue
+		}
+
+		if !join {
+			return true, nil
+		}
+
+		if !isSet(role["Joinable"]) {
+			return false, nil
+		}
+		found = true
+	}
+
+	return found, nil
+}
+
+// authorizeSync lets anyone sync just themselves, and role managers sync the
+// members of the roles they manage; anything bigger needs an admin.
+func (h *rolesHandler) authorizeSync(ctx context.Context, request *rolesrv.SyncRequest) error {
+	if !enforcePermissions {
+		return nil
+	}
+
+	user := actingUser(ctx)
+	if user != "" && len(request.UserIds) != 0 && len(request.Roles) == 0 {
+		if len(request.UserIds) == 1 && request.UserIds[0] == user {
+			return nil
+		}
+
+		managed, err := h.managedMembers(user)
+		if err != nil {
+			return err
+		}
+
+		allowed := true
+		for _, userId := range request.UserIds {
+			if !managed.Contains(userId) {
+				allowed = false
+				break
+			}
+		}
+
+		if allowed {
+			return nil
+		}
+	}
+
+	return h.authorize(ctx, "SyncToChatService", roleAdmins, sigAdmins)
+}
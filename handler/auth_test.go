@@ -0,0 +1,45 @@
+package handler
+
+import "testing"
+
+func TestSelfServiceFilter(t *testing.T) {
+	joinable := map[string]string{"Sig": "1", "Joinable": "1", "FilterB": "shared"}
+	closed := map[string]string{"Sig": "1", "FilterB": "shared"}
+	approval := map[string]string{"Sig": "1", "JoinApproval": "1", "FilterB": "shared"}
+	notSig := map[string]string{"Joinable": "1", "FilterB": "shared"}
+
+	tests := []struct {
+		name  string
+		roles []map[string]string
+		join  bool
+		want  bool
+	}{
+		{name: "join joinable", roles: []map[string]string{joinable}, join: true, want: true},
+		{name: "join closed", roles: []map[string]string{closed}, join: true},
+		{name: "join with a closed SIG sharing the filter", roles: []map[string]string{joinable, closed}, join: true},
+		{name: "join with an approval SIG sharing the filter", roles: []map[string]string{joinable, approval}, join: true},
+		{name: "join a role that isn't a SIG", roles: []map[string]string{notSig}, join: true},
+		{name: "leave closed", roles: []map[string]string{closed}, want: true},
+		{name: "leave a role that isn't a SIG", roles: []map[string]string{notSig}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHandler()
+			addTestFilter(t, h, "shared")
+
+			for r, role := range test.roles {
+				addTestRole(t, h, string('a'+rune(r)), role)
+			}
+
+			got, err := h.selfServiceFilter("shared", test.join)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	common "github.com/chremoas/services-common/command"
+	"github.com/chremoas/services-common/sets"
+	"github.com/google/uuid"
+	"golang.org/x/net/context"
+)
+
+// A role with a Capacity can't have more members than that. Joining a full
+// SIG is refused, or if it has Waitlist set puts you at the back of its
+// waitlist. Whenever someone leaves, the front of the waitlist is let in.
+// Admins adding members directly aren't held to the capacity.
+
+// Checking a SIG has room and adding someone to it have to be one step, or
+// people joining at the same time, possibly on different replicas, take it
+// over its capacity. So both happen under a short lived lock on the filter
+// being added to, a Redis key set the same way as the sync lock's.
+
+const (
+	admitLockKey  = "admit:lock:"
+	admitLockTTL  = 10 * time.Second
+	admitLockWait = 50 * time.Millisecond
+)
+
+// admitLock stands in for the Redis lock when there's no Redis, as in tests.
+var admitLock sync.Mutex
+
+// lockAdmissions waits until this replica is the only one adding people to
+// filter, for up to admitLockTTL. The returned func gives the lock back.
+func (h *rolesHandler) lockAdmissions(filter string) (func(), error) {
+	if h.Redis == nil {
+		admitLock.Lock()
+		return admitLock.Unlock, nil
+	}
+
+	key := h.Redis.KeyName(admitLockKey + filter)
+	owner := uuid.New().String()
+
+	for waited := time.Duration(0); waited < admitLockTTL; waited += admitLockWait {
+		ok, err := h.Redis.Client.SetNX(key, owner, admitLockTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return func() {
+				if err := releaseScript.Run(h.Redis.Client, []string{key}, owner).Err(); err != nil {
+					// It'll expire on its own
+					h.Logger.Sugar().Errorf("lockAdmissions: release %s: %s", filter, err)
+				}
+			}, nil
+		}
+
+		time.Sleep(admitLockWait)
+	}
+
+	return nil, fmt.Errorf("Timed out waiting to add members to `%s`.", filter)
+}
+
+// roleFull is true if the role has a capacity and its membership has reached
+// it.
+func (h *rolesHandler) roleFull(name string, role map[string]string) (bool, error) {
+	capacity, _ := strconv.Atoi(role["Capacity"])
+	if capacity <= 0 {
+		return false, nil
+	}
+
+	members, err := h.getRoleMembership(name)
+	if err != nil {
+		return false, err
+	}
+
+	return members.Len() >= capacity, nil
+}
+
+// checkFilterRoom returns an error if a SIG that filter decides the members
+// of is full.
+// The caller holds lockAdmissions(filter).
+func (h *rolesHandler) checkFilterRoom(filter string) error {
+	roles, err := h.getRoles()
+	if err != nil {
+		return err
+	}
+
+	for r := range roles {
+		role, err := h.getRole(roles[r])
+		if err != nil {
+			return err
+		}
+
+		if !isSet(role["Sig"]) || role["FilterB"] != filter {
+			continue
+		}
+
+		full, err := h.roleFull(roles[r], role)
+		if err != nil {
+			return err
+		}
+
+		if full {
+			return fmt.Errorf("`%s` is full.", roles[r])
+		}
+	}
+
+	return nil
+}
+
+// waitlistPosition is where user is on the waitlist of role, counting from 1,
+// or 0 if they aren't on it.
+func (h *rolesHandler) waitlistPosition(role, user string) (int, error) {
+	waiting, err := h.Store.GetWaitlist(role)
+	if err != nil {
+		return 0, err
+	}
+
+	for w := range waiting {
+		if waiting[w].User == user {
+			return w + 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// admit adds entry's user to the SIG's filter, or to its waitlist if it's
+// full, and records which in the audit log.
+func (h *rolesHandler) admit(actor, method string, role map[string]string, entry *storage.JoinRequest, before interface{}) (*rolesrv.JoinResult, error) {
+	unlock, err := h.lockAdmissions(role["FilterB"])
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	full, err := h.roleFull(entry.Role, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if !full {
+		if err := h.addJoinedMember(role["FilterB"], entry.User); err != nil {
+			return nil, err
+		}
+
+		h.audit(actor, method, entry.Role, before, map[string]string{"Filter": role["FilterB"], "Member": entry.User})
+		return &rolesrv.JoinResult{State: rolesrv.JoinState_JOINED}, nil
+	}
+
+	if !isSet(role["Waitlist"]) {
+		return nil, fmt.Errorf("`%s` is full.", entry.Role)
+	}
+
+	err = h.Store.AddToWaitlist(entry)
+
+	switch err {
+	case nil:
+	case storage.ErrWaitlisted:
+		return nil, fmt.Errorf("Already on the waitlist for `%s`.", entry.Role)
+	case storage.ErrRoleNotFound:
+		return nil, fmt.Errorf("Role `%s` doesn't exists.", entry.Role)
+	default:
+		return nil, err
+	}
+
+	position, err := h.waitlistPosition(entry.Role, entry.User)
+	if err != nil {
+		return nil, err
+	}
+
+	h.audit(actor, method, entry.Role, before, map[string]interface{}{"Waitlist": entry.User, "Position": position})
+	return &rolesrv.JoinResult{State: rolesrv.JoinState_WAITLISTED, Position: int32(position)}, nil
+}
+
+// JoinSIG adds the acting user to a joinable SIG, or its waitlist if it's
+// full, or asks to join one that needs approval. It doesn't sync; the caller
+// does that so the user hears how it went.
+func (h *rolesHandler) JoinSIG(ctx context.Context, request *rolesrv.JoinRequest, response *rolesrv.JoinResult) error {
+	user, err := joiningUser(ctx, "JoinSIG", request)
+	if err != nil {
+		return err
+	}
+
+	role, err := h.Store.GetRole(request.Role)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(role) == 0:
+		return fmt.Errorf("Role `%s` doesn't exists.", request.Role)
+	case !isSet(role["Sig"]):
+		return fmt.Errorf("`%s` is not a SIG.", request.Role)
+	case !isSet(role["Joinable"]) && isSet(role["JoinApproval"]):
+		response.State = rolesrv.JoinState_REQUESTED
+		return h.RequestJoin(ctx, request, &rolesrv.JoinRequest{})
+	case !isSet(role["Joinable"]):
+		return fmt.Errorf("`%s` is not a joinable SIG, talk to an admin.", request.Role)
+	}
+
+	if err := h.checkNotMember(request.Role, role, user); err != nil {
+		return err
+	}
+
+	result, err := h.admit(user, "JoinSIG", role, &storage.JoinRequest{
+		Role:      request.Role,
+		User:      user,
+		ChannelId: request.ChannelId,
+		Time:      time.Now().Unix(),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	*response = *result
+	return nil
+}
+
+func (h *rolesHandler) GetWaitlist(ctx context.Context, request *rolesrv.Role, response *rolesrv.JoinRequestList) error {
+	waiting, err := h.Store.GetWaitlist(request.ShortName)
+	if err != nil {
+		return err
+	}
+
+	for w := range waiting {
+		response.Requests = append(response.Requests, mapJoinRequestToProtobuf(&waiting[w]))
+	}
+
+	return nil
+}
+
+// RemoveFromWaitlist lets users give up their own place, and anyone who can
+// approve joins take someone else's.
+func (h *rolesHandler) RemoveFromWaitlist(ctx context.Context, request *rolesrv.JoinRequest, response *rolesrv.NilMessage) error {
+	if user := actingUser(ctx); user == "" || user != request.UserId {
+		role, err := h.Store.GetRole(request.Role)
+		if err != nil {
+			return err
+		}
+
+		if err := h.authorizeJoinDecision(ctx, "RemoveFromWaitlist", role); err != nil {
+			return err
+		}
+	}
+
+	err := h.Store.RemoveFromWaitlist(request.Role, request.UserId)
+
+	if err == storage.ErrNotWaitlisted {
+		return fmt.Errorf("%s isn't on the waitlist for `%s`.", request.UserId, request.Role)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	h.audit(actingUser(ctx), "RemoveFromWaitlist", request.Role, map[string]string{"Waitlist": request.UserId}, nil)
+
+	response = &rolesrv.NilMessage{}
+	return nil
+}
+
+// promotionQueue holds the filters members have left since promoteThread
+// last looked, so each SIG using one can let people in from its waitlist.
+// Adding never blocks.
+type promotionQueue struct {
+	mutex   sync.Mutex
+	filters *sets.StringSet
+	wake    chan struct{}
+}
+
+var promotions = newPromotionQueue()
+
+func newPromotionQueue() *promotionQueue {
+	return &promotionQueue{filters: sets.NewStringSet(), wake: make(chan struct{}, 1)}
+}
+
+func (q *promotionQueue) add(filters ...string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.filters.FromSlice(filters)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for filters to be added and takes them all off the queue.
+func (q *promotionQueue) next() *sets.StringSet {
+	for {
+		<-q.wake
+
+		q.mutex.Lock()
+		filters := q.filters
+		q.filters = sets.NewStringSet()
+		q.mutex.Unlock()
+
+		if filters.Len() > 0 {
+			return filters
+		}
+	}
+}
+
+// promoteThread lets people off waitlists in the background, so taking
+// members out of a filter doesn't wait on it.
+func (h *rolesHandler) promoteThread() {
+	for {
+		h.promoteWaitlists(promotions.next())
+	}
+}
+
+// promoteWaitlists lets people in from the front of the waitlist of every SIG
+// using one of filters as its FilterB that has room again, tells them, and
+// syncs them. Failures are logged; whoever was waiting stays on the waitlist.
+func (h *rolesHandler) promoteWaitlists(filters *sets.StringSet) {
+	sugar := h.Logger.Sugar()
+
+	roles, err := h.getRoles()
+	if err != nil {
+		sugar.Errorf("promoteWaitlists: getRoles: %s", err)
+		return
+	}
+
+	var promoted []string
+	for r := range roles {
+		role, err := h.Store.GetRole(roles[r])
+		if err != nil {
+			sugar.Errorf("promoteWaitlists: GetRole: %s: %s", roles[r], err)
+			continue
+		}
+
+		if capacity, _ := strconv.Atoi(role["Capacity"]); capacity <= 0 || !filters.Contains(role["FilterB"]) {
+			continue
+		}
+
+		promoted = append(promoted, h.promoteWaitlist(roles[r], role)...)
+	}
+
+	if len(promoted) > 0 {
+		syncControl.add(syncRequester{}, syncOptions{Scope: newSyncScope(promoted, nil)})
+	}
+}
+
+// promoteWaitlist lets people in from the front of one SIG's waitlist until
+// it's full again, and returns who got in.
+func (h *rolesHandler) promoteWaitlist(name string, role map[string]string) []string {
+	sugar := h.Logger.Sugar()
+
+	waiting, err := h.Store.GetWaitlist(name)
+	if err != nil {
+		sugar.Errorf("promoteWaitlists: GetWaitlist: %s: %s", name, err)
+		return nil
+	}
+
+	var promoted []string
+	for w := range waiting {
+		entry := &waiting[w]
+
+		admitted, err := h.promoteEntry(role, entry)
+
+		// Someone else may have got to them first
+		if err == storage.ErrNotWaitlisted {
+			continue
+		}
+
+		if err != nil {
+			sugar.Errorf("promoteWaitlists: %s: %s", name, err)
+		}
+
+		if !admitted {
+			break
+		}
+
+		promoted = append(promoted, entry.User)
+		h.audit("", "PromoteWaitlist", name, map[string]string{"Waitlist": entry.User},
+			map[string]string{"Filter": role["FilterB"], "Member": entry.User})
+		h.sendMessage(context.Background(), entry.ChannelId,
+			common.SendSuccess(fmt.Sprintf("A place opened up in %s, you're in", name), entry.User),
+			entry.ChannelId != "")
+	}
+
+	return promoted
+}
+
+// promoteEntry moves entry from the waitlist into the SIG if it has room. It
+// returns false once the SIG is full, or on an error, which is
+// storage.ErrNotWaitlisted if they've already left the waitlist.
+func (h *rolesHandler) promoteEntry(role map[string]string, entry *storage.JoinRequest) (bool, error) {
+	unlock, err := h.lockAdmissions(role["FilterB"])
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	full, err := h.roleFull(entry.Role, role)
+	if err != nil || full {
+		return false, err
+	}
+
+	if err := h.Store.RemoveFromWaitlist(entry.Role, entry.User); err != nil {
+		return false, err
+	}
+
+	if err := h.addJoinedMember(role["FilterB"], entry.User); err != nil {
+		if restoreErr := h.Store.AddToWaitlist(entry); restoreErr != nil {
+			h.Logger.Sugar().Errorf("promoteWaitlists: putting back %s/%s: %s", entry.Role, entry.User, restoreErr)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	"github.com/micro/go-micro/metadata"
+	"golang.org/x/net/context"
+)
+
+// newTestSig is a SIG on the filter "sig", which has members, with a
+// capacity and waitlist.
+func newTestSig(t *testing.T, capacity int, waitlist bool, members ...string) (*rolesHandler, map[string]string) {
+	h := newTestHandler()
+	addTestFilter(t, h, "sig", members...)
+
+	role := map[string]string{
+		"Sig":      "1",
+		"FilterA":  "wildcard",
+		"FilterB":  "sig",
+		"Capacity": strconv.Itoa(capacity),
+		"Waitlist": boolToString(waitlist),
+	}
+	addTestRole(t, h, "sig", role)
+
+	return h, role
+}
+
+func TestRoleFull(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		members  []string
+		full     bool
+	}{
+		{name: "no limit", members: []string{"1", "2"}},
+		{name: "room", capacity: 2, members: []string{"1"}},
+		{name: "at capacity", capacity: 2, members: []string{"1", "2"}, full: true},
+		{name: "over capacity", capacity: 1, members: []string{"1", "2"}, full: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, role := newTestSig(t, test.capacity, false, test.members...)
+
+			full, err := h.roleFull("sig", role)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if full != test.full {
+				t.Errorf("got %v, want %v", full, test.full)
+			}
+		})
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		waitlist bool
+		waiting  []string
+		state    rolesrv.JoinState
+		position int32
+		member   bool
+		err      bool
+	}{
+		{name: "no limit", state: rolesrv.JoinState_JOINED, member: true},
+		{name: "room", capacity: 2, state: rolesrv.JoinState_JOINED, member: true},
+		{name: "full", capacity: 1, err: true},
+		{name: "full with waitlist", capacity: 1, waitlist: true, state: rolesrv.JoinState_WAITLISTED, position: 1},
+		{name: "behind others", capacity: 1, waitlist: true, waiting: []string{"3", "4"},
+			state: rolesrv.JoinState_WAITLISTED, position: 3},
+		{name: "already waiting", capacity: 1, waitlist: true, waiting: []string{"2"}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, role := newTestSig(t, test.capacity, test.waitlist, "1")
+
+			for w, user := range test.waiting {
+				if err := h.Store.AddToWaitlist(&storage.JoinRequest{Role: "sig", User: user, Time: int64(w)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := h.admit("", "JoinSIG", role, &storage.JoinRequest{Role: "sig", User: "2", Time: 10}, nil)
+			if test.err {
+				if err == nil {
+					t.Fatalf("got %v, want an error", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.State != test.state || result.Position != test.position {
+				t.Errorf("got %v, want %v at %d", result, test.state, test.position)
+			}
+
+			members, _ := h.Store.GetFilterMembers("sig")
+			if validListItem("2", members) != test.member {
+				t.Errorf("member: %v, want %v", !test.member, test.member)
+			}
+		})
+	}
+}
+
+func TestAdmitConcurrent(t *testing.T) {
+	h, role := newTestSig(t, 5, false)
+
+	var wg sync.WaitGroup
+	for u := 0; u < 20; u++ {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			h.admit("", "JoinSIG", role, &storage.JoinRequest{Role: "sig", User: user}, nil)
+		}(strconv.Itoa(u))
+	}
+	wg.Wait()
+
+	members, _ := h.Store.GetFilterMembers("sig")
+	if len(members) != 5 {
+		t.Errorf("%d members, want the capacity of 5", len(members))
+	}
+}
+
+func TestAddMembersSelfJoinConcurrent(t *testing.T) {
+	h := newTestHandler()
+	addTestFilter(t, h, "sig")
+	addTestRole(t, h, "sig", map[string]string{
+		"Sig": "1", "Joinable": "1", "FilterA": "wildcard", "FilterB": "sig", "Capacity": "5",
+	})
+
+	enforcePermissions = true
+	defer func() { enforcePermissions = false }()
+
+	var wg sync.WaitGroup
+	for u := 0; u < 20; u++ {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			ctx := metadata.NewContext(context.Background(), metadata.Metadata{rolesrv.UserMetadataKey: user})
+			h.AddMembers(ctx, &rolesrv.Members{Name: []string{user}, Filter: "sig"}, &rolesrv.NilMessage{})
+		}(strconv.Itoa(u))
+	}
+	wg.Wait()
+
+	members, _ := h.Store.GetFilterMembers("sig")
+	if len(members) != 5 {
+		t.Errorf("%d members, want the capacity of 5", len(members))
+	}
+}
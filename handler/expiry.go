@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// expiryThread periodically takes members whose filter membership has expired
+// out of their filters and queues a sync so Discord catches up. How often it
+// checks is set by roles.expiryInterval (a Go duration, one minute by default).
+func (h *rolesHandler) expiryThread() {
+	interval := viper.GetDuration("roles.expiryInterval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	for range time.Tick(interval) {
+		h.removeExpiredMembers()
+	}
+}
+
+func (h *rolesHandler) removeExpiredMembers() {
+	sugar := h.Logger.Sugar()
+
+	now := time.Now().Unix()
+
+	expired, err := h.Store.GetExpiredMembers(now)
+	if err != nil {
+		sugar.Errorf("removeExpiredMembers: GetExpiredMembers: %s", err)
+		return
+	}
+
+	if len(expired) == 0 {
+		return
+	}
+
+	// Only the people who dropped out can have changed.
+	var users, filters []string
+	for filter, members := range expired {
+		// Anyone re-added since the check above stays
+		members, err := h.Store.RemoveExpiredMembers(filter, members, now)
+		if err != nil {
+			sugar.Errorf("removeExpiredMembers: RemoveExpiredMembers: %s: %s", filter, err)
+			continue
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		sugar.Infof("Removed %d expired members from %s: %v", len(members), filter, members)
+		h.audit("", "ExpireMembers", filter, members, nil)
+		users = append(users, members...)
+		filters = append(filters, filter)
+	}
+
+	if len(users) > 0 {
+		syncControl.add(syncRequester{}, syncOptions{Scope: newSyncScope(users, nil)})
+		promotions.add(filters...)
+	}
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/chremoas/services-common/sets"
+)
+
+// A filter expression combines filters with set operators:
+//
+//	a | b   or  a ∪ b   union
+//	a & b   or  a ∩ b   intersection
+//	a - b   or  a − b   difference
+//
+// Intersection binds tighter than union and difference, which are evaluated
+// left to right, and parentheses group as usual. So
+// "(pilots | alts) & capital_trained - banned" is everyone in pilots or alts
+// who is also in capital_trained, less anyone in banned. A filter whose name
+// contains one of the operator characters can be written in double quotes.
+
+type expression interface {
+	evaluate(members func(filter string) (*sets.StringSet, error)) (*sets.StringSet, error)
+	addFilters(filters *sets.StringSet)
+}
+
+type filterExpression string
+
+type binaryExpression struct {
+	op          rune
+	left, right expression
+}
+
+func (e filterExpression) evaluate(members func(string) (*sets.StringSet, error)) (*sets.StringSet, error) {
+	return members(string(e))
+}
+
+func (e filterExpression) addFilters(filters *sets.StringSet) {
+	filters.Add(string(e))
+}
+
+func (e *binaryExpression) evaluate(members func(string) (*sets.StringSet, error)) (*sets.StringSet, error) {
+	left, err := e.left.evaluate(members)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.right.evaluate(members)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case '|':
+		union := sets.NewStringSet()
+		union.FromSlice(left.ToSlice())
+		union.FromSlice(right.ToSlice())
+		return union, nil
+	case '&':
+		return left.Intersection(right), nil
+	default:
+		return left.Difference(right), nil
+	}
+}
+
+func (e *binaryExpression) addFilters(filters *sets.StringSet) {
+	e.left.addFilters(filters)
+	e.right.addFilters(filters)
+}
+
+// expressionFilters returns the name of every filter the expression uses.
+func expressionFilters(e expression) []string {
+	filters := sets.NewStringSet()
+	e.addFilters(filters)
+	return filters.ToSlice()
+}
+
+// operators maps every accepted operator spelling to its ASCII form.
+var operators = map[rune]rune{
+	'|': '|', '∪': '|',
+	'&': '&', '∩': '&',
+	'-': '-', '−': '-',
+}
+
+type token struct {
+	op   rune // one of |&-() or 0 for a filter name
+	name string
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{op: r})
+			i++
+		case operators[r] != 0:
+			tokens = append(tokens, token{op: operators[r]})
+			i++
+		case r == '"':
+			end := strings.IndexRune(string(runes[i+1:]), '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in `%s`", input)
+			}
+			name := string(runes[i+1:])[:end]
+			if name == "" {
+				return nil, fmt.Errorf("empty filter name in `%s`", input)
+			}
+			tokens = append(tokens, token{name: name})
+			i += len([]rune(name)) + 2
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && operators[runes[i]] == 0 &&
+				runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, token{name: string(runes[start:i])})
+		}
+	}
+
+	return tokens, nil
+}
+
+// parseExpression parses a filter expression, see the top of this file for
+// the syntax.
+func parseExpression(input string) (expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	p := &expressionParser{input: input, tokens: tokens}
+	e, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected `%s` in `%s`", p.tokens[p.pos], input)
+	}
+
+	return e, nil
+}
+
+func (t token) String() string {
+	if t.op != 0 {
+		return string(t.op)
+	}
+	return t.name
+}
+
+type expressionParser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) peek() (token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return token{}, false
+}
+
+// parseUnion handles | and -, the lowest precedence operators.
+func (p *expressionParser) parseUnion() (expression, error) {
+	left, err := p.parseIntersection()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok || (t.op != '|' && t.op != '-') {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseIntersection()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{op: t.op, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseIntersection() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok || t.op != '&' {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{op: '&', left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseOperand() (expression, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of `%s`", p.input)
+	}
+	p.pos++
+
+	switch t.op {
+	case 0:
+		return filterExpression(t.name), nil
+	case '(':
+		e, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+
+		if t, ok := p.peek(); !ok || t.op != ')' {
+			return nil, fmt.Errorf("missing `)` in `%s`", p.input)
+		}
+		p.pos++
+
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unexpected `%s` in `%s`", t, p.input)
+	}
+}
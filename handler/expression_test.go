@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chremoas/services-common/sets"
+)
+
+func TestParseExpression(t *testing.T) {
+	filters := map[string]*sets.StringSet{
+		"a":   newStringSet("1", "2"),
+		"b":   newStringSet("2", "3"),
+		"c":   newStringSet("3", "4"),
+		"x-y": newStringSet("5"),
+	}
+
+	members := func(filter string) (*sets.StringSet, error) {
+		if set, ok := filters[filter]; ok {
+			return set, nil
+		}
+		return nil, fmt.Errorf("no filter %s", filter)
+	}
+
+	tests := []struct {
+		input   string
+		filters []string
+		members []string
+		err     bool
+	}{
+		{input: "a", filters: []string{"a"}, members: []string{"1", "2"}},
+		{input: "a | b", filters: []string{"a", "b"}, members: []string{"1", "2", "3"}},
+		{input: "a & b", filters: []string{"a", "b"}, members: []string{"2"}},
+		{input: "a - b", filters: []string{"a", "b"}, members: []string{"1"}},
+		{input: "a ∪ b ∩ c", filters: []string{"a", "b", "c"}, members: []string{"1", "2", "3"}},
+		{input: "(a | b) & c", filters: []string{"a", "b", "c"}, members: []string{"3"}},
+		{input: "a | b − b", filters: []string{"a", "b"}, members: []string{"1"}},
+		{input: `"x-y" | a`, filters: []string{"x-y", "a"}, members: []string{"1", "2", "5"}},
+		{input: "", err: true},
+		{input: "a |", err: true},
+		{input: "| a", err: true},
+		{input: "(a | b", err: true},
+		{input: "a b", err: true},
+		{input: `"a`, err: true},
+		{input: `""`, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			e, err := parseExpression(test.input)
+			if test.err {
+				if err == nil {
+					t.Fatal("parsed, want an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkSet(t, newStringSet(expressionFilters(e)...), test.filters...)
+
+			set, err := e.evaluate(members)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkSet(t, set, test.members...)
+		})
+	}
+}
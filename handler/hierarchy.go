@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chremoas/services-common/sets"
+)
+
+// Roles can name parent roles. Everyone in a role is also counted as a member
+// of its parents (and their parents), so "FC" can sit under "Leadership"
+// without the FC filters being copied into Leadership's.
+
+// roleParents reads the comma separated Parents field of a stored role.
+func roleParents(role map[string]string) []string {
+	return commaList(role["Parents"])
+}
+
+// commaList splits a comma separated role field, dropping empty entries.
+func commaList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// membershipResolver works out role membership including everything inherited
+// from child roles. It loads the hierarchy once and caches each role's own
+// membership, so build one per request or sync rather than per role.
+type membershipResolver struct {
+	h        *rolesHandler
+	children map[string][]string
+	direct   map[string]*sets.StringSet
+}
+
+func (h *rolesHandler) newMembershipResolver() (*membershipResolver, error) {
+	roles, err := h.getRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]string)
+	for role := range roles {
+		r, err := h.Store.GetRole(roles[role])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range roleParents(r) {
+			children[parent] = append(children[parent], roles[role])
+		}
+	}
+
+	return &membershipResolver{h: h, children: children, direct: make(map[string]*sets.StringSet)}, nil
+}
+
+// membership returns the members of role and of every role below it.
+func (m *membershipResolver) membership(role string) (*sets.StringSet, error) {
+	members := sets.NewStringSet()
+	seen := sets.NewStringSet()
+	queue := []string{role}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		// AddRole and UpdateRole refuse cycles, but don't loop forever if one
+		// sneaks in anyway.
+		if seen.Contains(current) {
+			continue
+		}
+		seen.Add(current)
+
+		direct, ok := m.direct[current]
+		if !ok {
+			var err error
+			direct, err = m.h.getDirectRoleMembership(current)
+			if err != nil {
+				return nil, err
+			}
+			m.direct[current] = direct
+		}
+
+		members.FromSlice(direct.ToSlice())
+		queue = append(queue, m.children[current]...)
+	}
+
+	return members, nil
+}
+
+// childRoles returns the roles that name role as a parent, sorted.
+func (h *rolesHandler) childRoles(role string) ([]string, error) {
+	roles, err := h.getRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	var children []string
+	for r := range roles {
+		info, err := h.getRole(roles[r])
+		if err != nil {
+			return nil, err
+		}
+
+		if validListItem(role, roleParents(info)) {
+			children = append(children, roles[r])
+		}
+	}
+
+	sort.Strings(children)
+	return children, nil
+}
+
+// checkParents makes sure every parent exists and that giving role these
+// parents wouldn't make it its own ancestor.
+func (h *rolesHandler) checkParents(role string, parents []string) error {
+	for _, parent := range parents {
+		if parent == role {
+			return fmt.Errorf("Role `%s` can't be its own parent.", role)
+		}
+
+		exists, err := h.Store.RoleExists(parent)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("Parent role `%s` doesn't exists.", parent)
+		}
+	}
+
+	seen := sets.NewStringSet()
+	queue := append([]string{}, parents...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == role {
+			return fmt.Errorf("Role `%s` would end up as its own ancestor.", role)
+		}
+
+		if seen.Contains(current) {
+			continue
+		}
+		seen.Add(current)
+
+		r, err := h.Store.GetRole(current)
+		if err != nil {
+			return err
+		}
+
+		queue = append(queue, roleParents(r)...)
+	}
+
+	return nil
+}
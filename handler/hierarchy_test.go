@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"golang.org/x/net/context"
+)
+
+func TestCheckParents(t *testing.T) {
+	h := newTestHandler()
+	addTestRole(t, h, "top", map[string]string{})
+	addTestRole(t, h, "mid", map[string]string{"Parents": "top"})
+	addTestRole(t, h, "leaf", map[string]string{"Parents": "mid"})
+
+	tests := []struct {
+		name    string
+		role    string
+		parents []string
+		err     bool
+	}{
+		{name: "no parents", role: "leaf"},
+		{name: "grandparent", role: "leaf", parents: []string{"top"}},
+		{name: "new role", role: "new", parents: []string{"leaf", "top"}},
+		{name: "own parent", role: "top", parents: []string{"top"}, err: true},
+		{name: "missing parent", role: "top", parents: []string{"nope"}, err: true},
+		{name: "child as parent", role: "mid", parents: []string{"leaf"}, err: true},
+		{name: "grandchild as parent", role: "top", parents: []string{"mid", "leaf"}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := h.checkParents(test.role, test.parents)
+			if (err != nil) != test.err {
+				t.Errorf("got %v, want an error: %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestRemoveRoleWithChildren(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		children string
+	}{
+		{name: "parent of two", role: "top", children: "a, b"},
+		{name: "parent of one", role: "a", children: "leaf"},
+		{name: "no children", role: "leaf"},
+		{name: "sibling", role: "b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHandler()
+			addTestRole(t, h, "top", map[string]string{})
+			addTestRole(t, h, "b", map[string]string{"Parents": "top"})
+			addTestRole(t, h, "a", map[string]string{"Parents": "top"})
+			addTestRole(t, h, "leaf", map[string]string{"Parents": "a"})
+
+			err := h.RemoveRole(context.Background(), &rolesrv.Role{ShortName: test.role}, &rolesrv.NilMessage{})
+			exists, _ := h.Store.RoleExists(test.role)
+
+			if test.children == "" {
+				if err != nil || exists {
+					t.Fatalf("got %v, role still there: %v", err, exists)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.children) {
+				t.Fatalf("got %v, want an error naming %s", err, test.children)
+			}
+
+			if !exists {
+				t.Error("role removed despite its children")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	discord "github.com/chremoas/discord-gateway/proto"
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/services-common/sets"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+var notShortName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ImportRoles creates a Chremoas role for each Discord role it doesn't know
+// about yet, so a guild that already has roles can be taken on without the
+// first sync deleting them. Each role gets a filter of the same name holding
+// whoever has the role now, and is linked to the Discord role by Id, which
+// makes the next sync a no-op for it.
+func (h *rolesHandler) ImportRoles(ctx context.Context, request *rolesrv.ImportRolesRequest, response *rolesrv.ImportRolesResponse) error {
+	// A dry run lists every Discord role, so it needs the same permission
+	if err := h.authorize(ctx, "ImportRoles", roleAdmins); err != nil {
+		return err
+	}
+
+	discordRoles, err := clients.discord.GetAllRoles(ctx, &discord.GuildObjectRequest{})
+	if err != nil {
+		return err
+	}
+
+	members, err := getAllDiscordMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	holders := make(map[string][]string)
+	for _, member := range members {
+		for _, role := range member.Roles {
+			holders[role.Id] = append(holders[role.Id], member.User.Id)
+		}
+	}
+
+	// Roles Chremoas already has, by Discord Id and by name
+	known := sets.NewStringSet()
+	roles, err := h.getRoles()
+	if err != nil {
+		return err
+	}
+
+	for r := range roles {
+		role, err := h.getRole(roles[r])
+		if err != nil {
+			return err
+		}
+
+		known.Add(role["DiscordId"])
+		known.Add(role["Name"])
+	}
+
+	wanted := sets.NewStringSet()
+	wanted.FromSlice(request.Names)
+
+	// Names handed out during a dry run don't exist yet, so keep track of them
+	// here too
+	taken := sets.NewStringSet()
+
+	for _, role := range discordRoles.Roles {
+		if wanted.Len() != 0 && !wanted.Contains(role.Name) {
+			continue
+		}
+
+		switch {
+		case role.Name == "@everyone":
+			continue
+		case role.Name == viper.GetString("bot.botRole"):
+			response.Skipped = append(response.Skipped, fmt.Sprintf("%s: the bot's own role", role.Name))
+			continue
+		case known.Contains(role.Id) || known.Contains(role.Name):
+			response.Skipped = append(response.Skipped, fmt.Sprintf("%s: already a Chremoas role", role.Name))
+			continue
+		case role.Managed:
+			response.Skipped = append(response.Skipped, fmt.Sprintf("%s: managed by an integration", role.Name))
+			continue
+		case ignoreRole(role.Name):
+			response.Skipped = append(response.Skipped, fmt.Sprintf("%s: in bot.ignoredRoles", role.Name))
+			continue
+		}
+
+		shortName, err := h.importShortName(role.Name, taken)
+		if err != nil {
+			return err
+		}
+		taken.Add(shortName)
+
+		imported := &rolesrv.Role{
+			Type:        "discord",
+			ShortName:   shortName,
+			FilterA:     shortName,
+			FilterB:     "wildcard",
+			Sync:        true,
+			Name:        role.Name,
+			Color:       role.Color,
+			Hoist:       role.Hoist,
+			Position:    role.Position,
+			Permissions: role.Permissions,
+			Mentionable: role.Mentionable,
+			DiscordId:   role.Id,
+		}
+
+		if !request.DryRun {
+			if err := h.importRole(imported, holders[role.Id]); err != nil {
+				return fmt.Errorf("Importing `%s`: %s", role.Name, err)
+			}
+
+			h.audit(actingUser(ctx), "ImportRoles", shortName, nil, mapProtobufRoleToRole(imported))
+		}
+
+		response.Imported = append(response.Imported, imported)
+	}
+
+	return nil
+}
+
+// importShortName turns a Discord role name into a ShortName that isn't in
+// use as a role or a filter yet.
+func (h *rolesHandler) importShortName(name string, taken *sets.StringSet) (string, error) {
+	base := strings.Trim(notShortName.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" {
+		base = "role"
+	}
+
+	for i := 1; ; i++ {
+		shortName := base
+		if i > 1 {
+			shortName = fmt.Sprintf("%s_%d", base, i)
+		}
+
+		if taken.Contains(shortName) {
+			continue
+		}
+
+		roleExists, err := h.Store.RoleExists(shortName)
+		if err != nil {
+			return "", err
+		}
+
+		filterExists, err := h.Store.FilterExists(shortName)
+		if err != nil {
+			return "", err
+		}
+
+		if !roleExists && !filterExists {
+			return shortName, nil
+		}
+	}
+}
+
+// importRole creates the role and its filter, taking the filter away again if
+// the role can't be created so the next import doesn't trip over it.
+func (h *rolesHandler) importRole(role *rolesrv.Role, members []string) error {
+	err := h.Store.CreateFilter(role.ShortName, fmt.Sprintf("Imported from Discord role %s", role.Name))
+	if err != nil {
+		return err
+	}
+
+	if len(members) != 0 {
+		err = h.Store.AddFilterMembers(role.ShortName, members)
+	}
+
+	if err == nil {
+		err = h.Store.CreateRole(role.ShortName, mapProtobufRoleToRole(role), []string{role.ShortName})
+	}
+
+	if err != nil {
+		h.removeImportedFilter(role.ShortName, members)
+	}
+
+	return err
+}
+
+func (h *rolesHandler) removeImportedFilter(filter string, members []string) {
+	sugar := h.Logger.Sugar()
+
+	if err := h.Store.RemoveFilterMembers(filter, members); err != nil {
+		sugar.Errorf("importRole: removing the members of %s: %s", filter, err)
+		return
+	}
+
+	if err := h.Store.DeleteFilter(filter); err != nil {
+		sugar.Errorf("importRole: removing %s: %s", filter, err)
+	}
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	redis "github.com/chremoas/services-common/redis"
+	goredis "github.com/go-redis/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxJobErrors stops one very broken sync from eating all the memory.
+const maxJobErrors = 100
+
+// With more than one replica a job runs on whichever replica queued it, but
+// GetSyncJob and GetSyncHistory can land on any of them. So each job is also
+// written to Redis under sync:job:<id> whenever it changes, and
+// sync:jobs lists them oldest first for the history. Progress counters are
+// written at most once every jobSaveInterval; changes of state always are.
+
+const (
+	syncJobKey      = "sync:job:"
+	syncJobsKey     = "sync:jobs"
+	jobSaveInterval = time.Second
+)
+
+// syncJob tracks one sync request from the queue through to the end of the
+// run. Everything goes through the mutex since the sync thread updates it
+// while RPCs read it.
+type syncJob struct {
+	mutex sync.Mutex
+	job   rolesrv.SyncJob
+	list  *syncJobList
+	saved time.Time
+}
+
+func newSyncJob(channelId, userId string) *syncJob {
+	return &syncJob{job: rolesrv.SyncJob{
+		Id:        uuid.New().String(),
+		State:     rolesrv.SyncJobState_QUEUED,
+		ChannelId: channelId,
+		UserId:    userId,
+		Queued:    time.Now().Unix(),
+		Requests:  1,
+	}}
+}
+
+func (j *syncJob) start() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.job.State = rolesrv.SyncJobState_RUNNING
+	j.job.Started = time.Now().Unix()
+	j.save()
+}
+
+func (j *syncJob) finish() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.job.State = rolesrv.SyncJobState_SUCCEEDED
+	if len(j.job.Errors) > 0 {
+		j.job.State = rolesrv.SyncJobState_FAILED
+	}
+	j.job.Finished = time.Now().Unix()
+	j.save()
+}
+
+func (j *syncJob) addError(format string, args ...interface{}) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if len(j.job.Errors) < maxJobErrors {
+		j.job.Errors = append(j.job.Errors, fmt.Sprintf(format, args...))
+	}
+}
+
+// update changes the progress counters.
+func (j *syncJob) update(f func(job *rolesrv.SyncJob)) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	f(&j.job)
+	if time.Since(j.saved) >= jobSaveInterval {
+		j.save()
+	}
+}
+
+// save writes the job to Redis, if its list keeps them there. The caller
+// holds the mutex, which keeps the writes in order.
+func (j *syncJob) save() {
+	if j.list == nil || j.list.redis == nil {
+		return
+	}
+
+	j.saved = time.Now()
+	if err := j.list.save(&j.job); err != nil {
+		j.list.logger.Sugar().Errorf("syncJob: saving %s: %s", j.job.Id, err)
+	}
+}
+
+func (j *syncJob) snapshot() *rolesrv.SyncJob {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	job := j.job
+	job.Errors = append([]string(nil), j.job.Errors...)
+	return &job
+}
+
+// syncJobList remembers the most recent jobs, in Redis if there is one and
+// otherwise in memory. Jobs that haven't finished are never dropped, so the
+// list can briefly run over its limit.
+type syncJobList struct {
+	mutex  sync.Mutex
+	limit  int
+	jobs   []*syncJob
+	redis  *redis.Client
+	logger *zap.Logger
+}
+
+func (l *syncJobList) add(job *syncJob) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.redis != nil {
+		job.mutex.Lock()
+		job.list = l
+		job.save()
+		job.mutex.Unlock()
+
+		err := l.redis.Client.RPush(l.redis.KeyName(syncJobsKey), job.job.Id).Err()
+		if err == nil {
+			err = l.trim()
+		}
+		if err != nil {
+			l.logger.Sugar().Errorf("syncJobList: adding %s: %s", job.job.Id, err)
+		}
+		return
+	}
+
+	l.jobs = append(l.jobs, job)
+
+	for i := 0; i < len(l.jobs) && len(l.jobs) > l.limit; {
+		if finished(l.jobs[i].snapshot()) {
+			l.jobs = append(l.jobs[:i], l.jobs[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+func finished(job *rolesrv.SyncJob) bool {
+	return job.State == rolesrv.SyncJobState_SUCCEEDED || job.State == rolesrv.SyncJobState_FAILED
+}
+
+func (l *syncJobList) save(job *rolesrv.SyncJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return l.redis.Client.Set(l.redis.KeyName(syncJobKey+job.Id), data, 0).Err()
+}
+
+func (l *syncJobList) load(id string) (*rolesrv.SyncJob, error) {
+	data, err := l.redis.Client.Get(l.redis.KeyName(syncJobKey + id)).Bytes()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job := &rolesrv.SyncJob{}
+	return job, json.Unmarshal(data, job)
+}
+
+// trim drops the oldest finished jobs from Redis while there are more than
+// the limit. Every replica trims the same list, so a job another replica got
+// to first is just skipped.
+func (l *syncJobList) trim() error {
+	ids, err := l.redis.Client.LRange(l.redis.KeyName(syncJobsKey), 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	over := len(ids) - l.limit
+	for i := 0; i < len(ids) && over > 0; i++ {
+		job, err := l.load(ids[i])
+		if err != nil {
+			return err
+		}
+
+		if job != nil && !finished(job) {
+			continue
+		}
+
+		_, err = l.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+			pipe.LRem(l.redis.KeyName(syncJobsKey), 1, ids[i])
+			pipe.Del(l.redis.KeyName(syncJobKey + ids[i]))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		over--
+	}
+
+	return nil
+}
+
+// get returns the job with id, or nil if there isn't one.
+func (l *syncJobList) get(id string) (*rolesrv.SyncJob, error) {
+	if l.redis != nil {
+		return l.load(id)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, job := range l.jobs {
+		if job.job.Id == id {
+			return job.snapshot(), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// history returns every job it has, newest first.
+func (l *syncJobList) history() ([]*rolesrv.SyncJob, error) {
+	var jobs []*rolesrv.SyncJob
+
+	if l.redis != nil {
+		ids, err := l.redis.Client.LRange(l.redis.KeyName(syncJobsKey), 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(ids) - 1; i >= 0; i-- {
+			job, err := l.load(ids[i])
+			if err != nil {
+				return nil, err
+			}
+
+			// Trimmed since the list was read
+			if job != nil {
+				jobs = append(jobs, job)
+			}
+		}
+
+		return jobs, nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := len(l.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, l.jobs[i].snapshot())
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	common "github.com/chremoas/services-common/command"
+	microerrors "github.com/micro/go-micro/errors"
+	"golang.org/x/net/context"
+)
+
+// A SIG that isn't Joinable but has JoinApproval set takes join requests
+// instead. Its managers, or sig_admins, approve or deny them; approving adds
+// the user to the SIG's filter (or its waitlist, if it's full) and syncs them,
+// and either way they're told in the channel they asked from.
+
+// authorizeJoinDecision lets a role's managers and sig_admins approve or deny
+// requests to join it.
+func (h *rolesHandler) authorizeJoinDecision(ctx context.Context, method string, role map[string]string) error {
+	if !enforcePermissions {
+		return nil
+	}
+
+	if user := actingUser(ctx); user != "" {
+		manager, err := h.isManager(user, role)
+		if err != nil {
+			return err
+		}
+
+		if manager {
+			return nil
+		}
+	}
+
+	return h.authorize(ctx, method, sigAdmins)
+}
+
+func (h *rolesHandler) RequestJoin(ctx context.Context, request *rolesrv.JoinRequest, response *rolesrv.JoinRequest) error {
+	user, err := joiningUser(ctx, "RequestJoin", request)
+	if err != nil {
+		return err
+	}
+
+	role, err := h.Store.GetRole(request.Role)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(role) == 0:
+		return fmt.Errorf("Role `%s` doesn't exists.", request.Role)
+	case !isSet(role["Sig"]):
+		return fmt.Errorf("`%s` is not a SIG.", request.Role)
+	case isSet(role["Joinable"]):
+		return fmt.Errorf("`%s` is joinable, just join it.", request.Role)
+	case !isSet(role["JoinApproval"]):
+		return fmt.Errorf("`%s` is not taking join requests, talk to an admin.", request.Role)
+	}
+
+	if err := h.checkNotMember(request.Role, role, user); err != nil {
+		return err
+	}
+
+	joinRequest := &storage.JoinRequest{
+		Role:      request.Role,
+		User:      user,
+		ChannelId: request.ChannelId,
+		Time:      time.Now().Unix(),
+	}
+
+	err = h.Store.AddJoinRequest(joinRequest)
+
+	switch err {
+	case nil:
+	case storage.ErrJoinRequestExists:
+		return fmt.Errorf("Already asked to join `%s`.", request.Role)
+	case storage.ErrRoleNotFound:
+		return fmt.Errorf("Role `%s` doesn't exists.", request.Role)
+	default:
+		return err
+	}
+
+	h.audit(user, "RequestJoin", request.Role, nil, joinRequest)
+
+	*response = *mapJoinRequestToProtobuf(joinRequest)
+	return nil
+}
+
+// joiningUser is who is joining: the acting user, who may only join for
+// themselves.
+func joiningUser(ctx context.Context, method string, request *rolesrv.JoinRequest) (string, error) {
+	user := request.UserId
+	if enforcePermissions {
+		user = actingUser(ctx)
+		if user == "" || (request.UserId != "" && request.UserId != user) {
+			return "", microerrors.Forbidden(serviceName, "%s: users can only join for themselves", method)
+		}
+	}
+
+	if len(user) == 0 {
+		return "", errors.New("UserId is required.")
+	}
+
+	return user, nil
+}
+
+// checkNotMember refuses to let a SIG's members join it again.
+func (h *rolesHandler) checkNotMember(name string, role map[string]string, user string) error {
+	members, err := h.Store.GetFilterMembers(role["FilterB"])
+	if err != nil {
+		return err
+	}
+
+	if validListItem(user, members) {
+		return fmt.Errorf("Already a member of `%s`.", name)
+	}
+
+	return nil
+}
+
+// GetJoinRequests returns the requests the acting user can decide. Asking for
+// one role they can't decide is refused; asking for every role just leaves
+// those out.
+func (h *rolesHandler) GetJoinRequests(ctx context.Context, request *rolesrv.JoinRequestQuery, response *rolesrv.JoinRequestList) error {
+	requests, err := h.Store.GetJoinRequests(request.Role)
+	if err != nil {
+		return err
+	}
+
+	allowed := make(map[string]bool)
+	for r := range requests {
+		role := requests[r].Role
+
+		if _, checked := allowed[role]; !checked {
+			fields, err := h.Store.GetRole(role)
+			if err != nil {
+				return err
+			}
+
+			err = h.authorizeJoinDecision(ctx, "GetJoinRequests", fields)
+			if err != nil && (request.Role != "" || !isForbidden(err)) {
+				return err
+			}
+			allowed[role] = err == nil
+		}
+
+		if allowed[role] {
+			response.Requests = append(response.Requests, mapJoinRequestToProtobuf(&requests[r]))
+		}
+	}
+
+	return nil
+}
+
+func (h *rolesHandler) ApproveJoinRequest(ctx context.Context, request *rolesrv.JoinRequest, response *rolesrv.NilMessage) error {
+	return h.decideJoinRequest(ctx, "ApproveJoinRequest", request, true)
+}
+
+func (h *rolesHandler) DenyJoinRequest(ctx context.Context, request *rolesrv.JoinRequest, response *rolesrv.NilMessage) error {
+	return h.decideJoinRequest(ctx, "DenyJoinRequest", request, false)
+}
+
+func (h *rolesHandler) decideJoinRequest(ctx context.Context, method string, request *rolesrv.JoinRequest, approve bool) error {
+	role, err := h.Store.GetRole(request.Role)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorizeJoinDecision(ctx, method, role); err != nil {
+		return err
+	}
+
+	pending, err := h.Store.GetJoinRequests(request.Role)
+	if err != nil {
+		return err
+	}
+
+	var joinRequest *storage.JoinRequest
+	for p := range pending {
+		if pending[p].User == request.UserId {
+			joinRequest = &pending[p]
+		}
+	}
+
+	if joinRequest == nil {
+		return fmt.Errorf("No request from %s to join `%s`.", request.UserId, request.Role)
+	}
+
+	// Taking the request out first means only one manager gets to decide it
+	err = h.Store.RemoveJoinRequest(joinRequest.Role, joinRequest.User)
+	if err == storage.ErrJoinRequestNotFound {
+		return fmt.Errorf("The request from %s to join `%s` has already been decided.", request.UserId, request.Role)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !approve {
+		h.audit(actingUser(ctx), method, request.Role, joinRequest, nil)
+		h.sendMessage(ctx, joinRequest.ChannelId,
+			common.SendError(fmt.Sprintf("Your request to join %s was turned down", request.Role), joinRequest.User),
+			joinRequest.ChannelId != "")
+		return nil
+	}
+
+	// A full SIG puts them on its waitlist instead
+	result, err := h.admit(actingUser(ctx), method, role, joinRequest, joinRequest)
+	if err != nil {
+		if restoreErr := h.Store.AddJoinRequest(joinRequest); restoreErr != nil {
+			h.Logger.Sugar().Errorf("%s: putting back %s/%s: %s", method, joinRequest.Role, joinRequest.User, restoreErr)
+		}
+		return err
+	}
+
+	if result.State == rolesrv.JoinState_WAITLISTED {
+		h.sendMessage(ctx, joinRequest.ChannelId,
+			common.SendSuccess(fmt.Sprintf("Your request to join %s was approved, but it's full. You're number %d on the waitlist",
+				request.Role, result.Position), joinRequest.User),
+			joinRequest.ChannelId != "")
+		return nil
+	}
+
+	syncControl.add(syncRequester{}, syncOptions{Scope: newSyncScope([]string{joinRequest.User}, nil)})
+	h.sendMessage(ctx, joinRequest.ChannelId,
+		common.SendSuccess(fmt.Sprintf("You've been let into %s", request.Role), joinRequest.User),
+		joinRequest.ChannelId != "")
+
+	return nil
+}
+
+// addJoinedMember puts user in filter for good, the same way AddMembers
+// would.
+func (h *rolesHandler) addJoinedMember(filter, user string) error {
+	exists, err := h.Store.FilterExists(filter)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("Filter `%s` doesn't exists.", filter)
+	}
+
+	if err := h.Store.AddFilterMembers(filter, []string{user}); err != nil {
+		return err
+	}
+
+	return h.Store.SetMemberExpiries(filter, map[string]int64{user: 0})
+}
+
+func mapJoinRequestToProtobuf(request *storage.JoinRequest) *rolesrv.JoinRequest {
+	return &rolesrv.JoinRequest{
+		Role:      request.Role,
+		UserId:    request.User,
+		ChannelId: request.ChannelId,
+		Time:      request.Time,
+	}
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	redis "github.com/chremoas/services-common/redis"
+	goredis "github.com/go-redis/redis"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+// With more than one replica every one of them has a sync thread, and two
+// syncs editing the guild at once undo each other's work. So a replica has to
+// hold a lease in Redis to run a sync: requests still queue on whichever
+// replica got them, they just take turns.
+//
+// The lease is a key holding the replica's name that expires unless the
+// holder keeps renewing it. Each new holder takes the next number from a
+// counter as its fencing token. Discord can't check a token for us, so the
+// sync checks it is still the current holder before every change it makes
+// instead, and stops if it isn't. That can't close the gap completely but it
+// keeps a replica that stalled past its lease from carrying on for long.
+
+const (
+	syncLockKey  = "sync:lock"
+	syncFenceKey = "sync:fence"
+)
+
+// ErrSyncLockLost is what a sync fails with once another replica has the lock.
+var ErrSyncLockLost = errors.New("lost the sync lock to another replica")
+
+var renewScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+var checkScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] and redis.call("GET", KEYS[2]) == ARGV[2] then
+	return 1
+end
+return 0`)
+
+// syncLock is this replica's handle on the lease. roles.syncLockTTL (a Go
+// duration, 30 seconds by default) is how long the lease lasts without being
+// renewed; it's renewed and retried at a third of that.
+type syncLock struct {
+	redis  *redis.Client
+	logger *zap.Logger
+	owner  string
+	ttl    time.Duration
+}
+
+func newSyncLock(redisClient *redis.Client, logger *zap.Logger) *syncLock {
+	ttl := viper.GetDuration("roles.syncLockTTL")
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "role-srv"
+	}
+
+	return &syncLock{
+		redis:  redisClient,
+		logger: logger,
+		owner:  fmt.Sprintf("%s-%s", host, uuid.New().String()[:8]),
+		ttl:    ttl,
+	}
+}
+
+// syncLease is one hold of the lock. It renews itself until released.
+type syncLease struct {
+	lock  *syncLock
+	fence int64
+	done  chan struct{}
+
+	mutex sync.Mutex
+	lost  bool
+}
+
+// acquire waits until this replica has the lock.
+func (l *syncLock) acquire() *syncLease {
+	sugar := l.logger.Sugar()
+
+	for {
+		ok, err := l.redis.Client.SetNX(l.redis.KeyName(syncLockKey), l.owner, l.ttl).Result()
+		if err != nil {
+			sugar.Errorf("syncLock: SetNX: %s", err)
+		}
+
+		if ok {
+			fence, err := l.redis.Client.Incr(l.redis.KeyName(syncFenceKey)).Result()
+			if err != nil {
+				sugar.Errorf("syncLock: Incr: %s", err)
+				l.redis.Client.Del(l.redis.KeyName(syncLockKey))
+			} else {
+				lease := &syncLease{lock: l, fence: fence, done: make(chan struct{})}
+				go lease.renew()
+				sugar.Infof("Took the sync lock as %s with fence %d", l.owner, fence)
+				return lease
+			}
+		}
+
+		time.Sleep(l.ttl / 3)
+	}
+}
+
+func (s *syncLease) renew() {
+	l := s.lock
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := renewScript.Run(l.redis.Client, []string{l.redis.KeyName(syncLockKey)},
+			l.owner, l.ttl.Nanoseconds()/int64(time.Millisecond)).Int64()
+		if err != nil {
+			// Keep trying, the lease might outlast a blip.
+			l.logger.Sugar().Errorf("syncLock: renew: %s", err)
+			continue
+		}
+
+		if renewed == 0 {
+			l.logger.Sugar().Errorf("syncLock: lease with fence %d ran out", s.fence)
+			s.mutex.Lock()
+			s.lost = true
+			s.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// check returns ErrSyncLockLost unless this is still the current hold of the
+// lock. A nil lease is a sync that doesn't change anything, so it always
+// passes.
+func (s *syncLease) check() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	lost := s.lost
+	s.mutex.Unlock()
+
+	if lost {
+		return ErrSyncLockLost
+	}
+
+	l := s.lock
+	held, err := checkScript.Run(l.redis.Client,
+		[]string{l.redis.KeyName(syncLockKey), l.redis.KeyName(syncFenceKey)},
+		l.owner, strconv.FormatInt(s.fence, 10)).Int64()
+	if err != nil {
+		return err
+	}
+
+	if held == 0 {
+		return ErrSyncLockLost
+	}
+
+	return nil
+}
+
+func (s *syncLease) release() {
+	close(s.done)
+
+	l := s.lock
+	err := releaseScript.Run(l.redis.Client, []string{l.redis.KeyName(syncLockKey)}, l.owner).Err()
+	if err != nil {
+		// It'll expire on its own
+		l.logger.Sugar().Errorf("syncLock: release: %s", err)
+	}
+}
+
+func (h *rolesHandler) GetSyncLock(ctx context.Context, request *rolesrv.NilMessage, response *rolesrv.SyncLock) error {
+	holder, err := h.Redis.Client.Get(h.Redis.KeyName(syncLockKey)).Result()
+	if err != nil && err != goredis.Nil {
+		return err
+	}
+
+	fence, err := h.Redis.Client.Get(h.Redis.KeyName(syncFenceKey)).Int64()
+	if err != nil && err != goredis.Nil {
+		return err
+	}
+
+	response.Holder = holder
+	response.Fence = fence
+
+	if holder != "" {
+		ttl, err := h.Redis.Client.PTTL(h.Redis.KeyName(syncLockKey)).Result()
+		if err != nil {
+			return err
+		}
+
+		if ttl > 0 {
+			response.Expires = time.Now().Add(ttl).UnixNano() / int64(time.Millisecond)
+		}
+	}
+
+	return nil
+}
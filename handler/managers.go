@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	"github.com/chremoas/services-common/sets"
+	"golang.org/x/net/context"
+)
+
+// A role can name managers: users, and the members of a filter, who look
+// after that one role without being role or SIG admins. They can change who
+// is in the role's FilterB and the fields that only change how it looks.
+// Filters can be shared, so a manager can only change a FilterB that no role
+// they don't manage uses.
+
+// managerKeys are the role fields a manager may change.
+var managerKeys = []string{"Name", "Color", "Hoist", "Position", "Mentionable"}
+
+// isManager is true if user manages role.
+func (h *rolesHandler) isManager(user string, role map[string]string) (bool, error) {
+	for _, manager := range commaList(role["Managers"]) {
+		if manager == user {
+			return true, nil
+		}
+	}
+
+	if role["ManagerFilter"] == "" {
+		return false, nil
+	}
+
+	members, err := h.Store.GetFilterMembers(role["ManagerFilter"])
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range members {
+		if member == user {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// managesFilter is true if user may change the members of filter: it's the
+// FilterB of a role they manage, and every other role using it is one they
+// manage too. A filter that decides who manages a role is left to admins.
+func (h *rolesHandler) managesFilter(user, filter string) (bool, error) {
+	roles, err := h.getRoles()
+	if err != nil {
+		return false, err
+	}
+
+	manages := false
+	for r := range roles {
+		role, err := h.getRole(roles[r])
+		if err != nil {
+			return false, err
+		}
+
+		if role["ManagerFilter"] == filter {
+			return false, nil
+		}
+
+		if !roleFilters(role).Contains(filter) {
+			continue
+		}
+
+		manager, err := h.isManager(user, role)
+		if err != nil {
+			return false, err
+		}
+
+		if !manager {
+			return false, nil
+		}
+
+		if role["FilterB"] == filter {
+			manages = true
+		}
+	}
+
+	return manages, nil
+}
+
+// roleFilters is every filter the role's membership depends on.
+func roleFilters(role map[string]string) *sets.StringSet {
+	filters := sets.NewStringSet()
+	filters.Add(role["FilterA"])
+	filters.Add(role["FilterB"])
+	if role["Expression"] != "" {
+		if expression, err := parseExpression(role["Expression"]); err == nil {
+			filters.FromSlice(expressionFilters(expression))
+		}
+	}
+	return filters
+}
+
+// managedMembers is everyone in the roles user manages, including whoever was
+// in them at the last sync, so the users a manager has just taken out count
+// too.
+func (h *rolesHandler) managedMembers(user string) (*sets.StringSet, error) {
+	members := sets.NewStringSet()
+
+	roles, err := h.getRoles()
+	if err != nil {
+		return members, err
+	}
+
+	resolver, err := h.newMembershipResolver()
+	if err != nil {
+		return members, err
+	}
+
+	for r := range roles {
+		role, err := h.getRole(roles[r])
+		if err != nil {
+			return members, err
+		}
+
+		manager, err := h.isManager(user, role)
+		if err != nil {
+			return members, err
+		}
+
+		if !manager {
+			continue
+		}
+
+		current, err := resolver.membership(roles[r])
+		if err != nil {
+			return members, err
+		}
+		members.FromSlice(current.ToSlice())
+
+		if last, ok := syncedMembership.get(roles[r]); ok {
+			members.FromSlice(last.ToSlice())
+		}
+	}
+
+	return members, nil
+}
+
+func (h *rolesHandler) GetRoleManagers(ctx context.Context, request *rolesrv.Role, response *rolesrv.RoleManagers) error {
+	role, err := h.getRole(request.ShortName)
+	if err != nil {
+		return err
+	}
+
+	response.ShortName = request.ShortName
+	response.Users = commaList(role["Managers"])
+	response.Filter = role["ManagerFilter"]
+	return nil
+}
+
+func (h *rolesHandler) AddRoleManagers(ctx context.Context, request *rolesrv.RoleManagers, response *rolesrv.RoleManagers) error {
+	return h.changeRoleManagers(ctx, "AddRoleManagers", request, response, true)
+}
+
+func (h *rolesHandler) RemoveRoleManagers(ctx context.Context, request *rolesrv.RoleManagers, response *rolesrv.RoleManagers) error {
+	return h.changeRoleManagers(ctx, "RemoveRoleManagers", request, response, false)
+}
+
+func (h *rolesHandler) changeRoleManagers(ctx context.Context, method string, request *rolesrv.RoleManagers, response *rolesrv.RoleManagers, add bool) error {
+	if err := h.authorizeRole(ctx, method, request.ShortName); err != nil {
+		return err
+	}
+
+	role, err := h.getRole(request.ShortName)
+	if err != nil {
+		return err
+	}
+
+	managers := sets.NewStringSet()
+	managers.FromSlice(commaList(role["Managers"]))
+	filter := role["ManagerFilter"]
+
+	for _, user := range request.Users {
+		if add {
+			managers.Add(user)
+		} else {
+			managers.Remove(user)
+		}
+	}
+
+	var filters []string
+	switch {
+	case add && request.Filter != "":
+		filter = request.Filter
+		filters = append(filters, filter)
+	case !add && request.Filter == filter:
+		filter = ""
+	}
+
+	users := managers.ToSlice()
+	sort.Strings(users)
+	err = h.Store.UpdateRole(request.ShortName, map[string]string{
+		"Managers":      strings.Join(users, ","),
+		"ManagerFilter": filter,
+	}, filters)
+
+	var missingFilter *storage.MissingFilterError
+	switch {
+	case err == storage.ErrRoleNotFound:
+		return fmt.Errorf("Role `%s` doesn't exists.", request.ShortName)
+	case errors.As(err, &missingFilter):
+		return fmt.Errorf("Filter `%s` doesn't exists.", missingFilter.Name)
+	case err != nil:
+		return err
+	}
+
+	h.audit(actingUser(ctx), method, request.ShortName,
+		map[string]string{"Managers": role["Managers"], "ManagerFilter": role["ManagerFilter"]},
+		map[string]string{"Managers": strings.Join(users, ","), "ManagerFilter": filter})
+
+	response.ShortName = request.ShortName
+	response.Users = users
+	response.Filter = filter
+	return nil
+}
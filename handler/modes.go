@@ -0,0 +1,39 @@
+package handler
+
+import (
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/services-common/sets"
+)
+
+func syncRequestOptions(request *rolesrv.SyncRequest) syncOptions {
+	return syncOptions{
+		Scope:             newSyncScope(request.UserIds, request.Roles),
+		Mode:              request.Mode,
+		PreserveUnmanaged: request.PreserveUnmanaged,
+	}
+}
+
+// targetRoles is the set of roles a user should end up with on Discord, given
+// the roles they have now, the ones Chremoas says they should have and every
+// role Chremoas syncs members for. A role is unmanaged if Chremoas doesn't
+// sync its members, whether it's a Chremoas role with Sync off or one only
+// Discord knows about.
+func (o syncOptions) targetRoles(current, desired, managed *sets.StringSet) *sets.StringSet {
+	keep := sets.NewStringSet()
+	keep.FromSlice(desired.ToSlice())
+	if o.PreserveUnmanaged {
+		keep.FromSlice(current.Difference(managed).ToSlice())
+	}
+
+	switch o.Mode {
+	case rolesrv.SyncMode_ADD_ONLY:
+		target := sets.NewStringSet()
+		target.FromSlice(current.ToSlice())
+		target.FromSlice(desired.ToSlice())
+		return target
+	case rolesrv.SyncMode_REMOVE_ONLY:
+		return current.Intersection(keep)
+	default:
+		return keep
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"testing"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+)
+
+func TestTargetRoles(t *testing.T) {
+	// kept is both had and wanted, stale is had but no longer wanted, new is
+	// wanted but not had, and unmanaged isn't synced by Chremoas at all.
+	current := newStringSet("kept", "stale", "unmanaged")
+	desired := newStringSet("kept", "new")
+	managed := newStringSet("kept", "stale", "new")
+
+	tests := []struct {
+		name    string
+		options syncOptions
+		want    []string
+	}{
+		{name: "full", options: syncOptions{Mode: rolesrv.SyncMode_FULL},
+			want: []string{"kept", "new"}},
+		{name: "full preserving unmanaged", options: syncOptions{Mode: rolesrv.SyncMode_FULL, PreserveUnmanaged: true},
+			want: []string{"kept", "new", "unmanaged"}},
+		{name: "add only", options: syncOptions{Mode: rolesrv.SyncMode_ADD_ONLY},
+			want: []string{"kept", "new", "stale", "unmanaged"}},
+		{name: "add only preserving unmanaged", options: syncOptions{Mode: rolesrv.SyncMode_ADD_ONLY, PreserveUnmanaged: true},
+			want: []string{"kept", "new", "stale", "unmanaged"}},
+		{name: "remove only", options: syncOptions{Mode: rolesrv.SyncMode_REMOVE_ONLY},
+			want: []string{"kept"}},
+		{name: "remove only preserving unmanaged", options: syncOptions{Mode: rolesrv.SyncMode_REMOVE_ONLY, PreserveUnmanaged: true},
+			want: []string{"kept", "unmanaged"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkSet(t, test.options.targetRoles(current, desired, managed), test.want...)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"sort"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/services-common/sets"
+	"golang.org/x/net/context"
+)
+
+// PlanSync runs the same comparison as SyncToChatService and returns what it
+// would change, without calling anything on the gateway that changes Discord.
+// The member changes assume the role changes have been made, so roles that
+// would be created show up in users' additions. It also lists the roles the
+// sync would leave alone for sharing a name, and those it would link to their
+// Discord role by name. A limited request plans the same limited sync, which
+// leaves the roles alone.
+func (h *rolesHandler) PlanSync(ctx context.Context, request *rolesrv.SyncRequest, response *rolesrv.SyncPlan) error {
+	plan := syncData{Requesters: []syncRequester{{
+		ChannelId:   request.ChannelId,
+		UserId:      request.UserId,
+		SendMessage: request.SendMessage,
+	}}, syncOptions: syncRequestOptions(request)}
+
+	var users *sets.StringSet
+	if plan.Scope != nil {
+		var err error
+		if users, err = h.scopeUsers(plan.Scope); err != nil {
+			return err
+		}
+	}
+
+	roles := &rolePlan{}
+	if users == nil {
+		var err error
+		if roles, err = h.planRoles(plan); err != nil {
+			return err
+		}
+	}
+
+	members, err := h.planMembers(plan, users)
+	if err != nil {
+		return err
+	}
+
+	for _, create := range roles.create {
+		response.CreateRoles = append(response.CreateRoles, create.name)
+	}
+
+	response.DeleteRoles = roles.delete
+
+	for _, edit := range roles.edit {
+		response.EditRoles = append(response.EditRoles, &rolesrv.RoleEdit{Name: edit.request.Name, Changes: edit.changes})
+	}
+
+	response.SkipRoles = roles.skip
+
+	for shortName, id := range roles.link {
+		response.LinkRoles = append(response.LinkRoles, &rolesrv.RoleLink{ShortName: shortName, DiscordId: id})
+	}
+	sort.Slice(response.LinkRoles, func(i, j int) bool {
+		return response.LinkRoles[i].ShortName < response.LinkRoles[j].ShortName
+	})
+
+	for _, change := range members.changes {
+		response.Members = append(response.Members, &rolesrv.MemberChange{
+			UserId:   change.userId,
+			Username: members.username[change.userId],
+			Add:      change.add,
+			Remove:   change.remove,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"sync"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+)
+
+// syncRequester is someone who asked for a sync and where to tell them how it
+// went. SendMessage gets them every progress message, Summary just one at the
+// end.
+type syncRequester struct {
+	ChannelId   string
+	UserId      string
+	SendMessage bool
+	Summary     bool
+}
+
+// syncOptions is what a sync covers and how far it may go. A nil Scope is
+// every user.
+type syncOptions struct {
+	Scope             *syncScope
+	Mode              rolesrv.SyncMode
+	PreserveUnmanaged bool
+}
+
+// mergeable is true if one sync can do what both asked for: the same mode,
+// and both keeping or both removing unmanaged roles. Anything else would take
+// away roles one of them asked to keep.
+func (o syncOptions) mergeable(other syncOptions) bool {
+	return o.Mode == other.Mode && o.PreserveUnmanaged == other.PreserveUnmanaged
+}
+
+// merge returns options covering both scopes. Only mergeable options can be
+// merged.
+func (o syncOptions) merge(other syncOptions) syncOptions {
+	return syncOptions{
+		Scope:             o.Scope.merge(other.Scope),
+		Mode:              o.Mode,
+		PreserveUnmanaged: o.PreserveUnmanaged,
+	}
+}
+
+// syncData is one run of the sync and everyone waiting on it. Lease is the
+// sync lock while the run holds it.
+type syncData struct {
+	Requesters []syncRequester
+	syncOptions
+	Job   *syncJob
+	Lease *syncLease
+}
+
+// syncQueue holds the pending syncs, at most one for each mode and
+// PreserveUnmanaged. Anything asked for while a mergeable sync is already
+// waiting is folded into it rather than queued behind it; the requester is
+// added to the list of channels the run reports to. Adding never blocks.
+type syncQueue struct {
+	mutex   sync.Mutex
+	pending []*syncData
+	wake    chan struct{}
+}
+
+func newSyncQueue() *syncQueue {
+	return &syncQueue{wake: make(chan struct{}, 1)}
+}
+
+// add queues a sync for requester and returns its job, and whether there was
+// already a mergeable sync waiting that it was folded into, in which case the
+// scopes are merged.
+func (q *syncQueue) add(requester syncRequester, options syncOptions) (*syncJob, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, pending := range q.pending {
+		if pending.syncOptions.mergeable(options) {
+			pending.addRequester(requester)
+			pending.syncOptions = pending.syncOptions.merge(options)
+			pending.Job.update(func(j *rolesrv.SyncJob) { j.Requests++ })
+			return pending.Job, true
+		}
+	}
+
+	job := newSyncJob(requester.ChannelId, requester.UserId)
+	q.pending = append(q.pending, &syncData{Requesters: []syncRequester{requester}, syncOptions: options, Job: job})
+	syncJobs.add(job)
+
+	q.signal()
+
+	return job, false
+}
+
+// signal wakes next without blocking.
+func (q *syncQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for a pending sync and takes the oldest off the queue.
+func (q *syncQueue) next() syncData {
+	for {
+		<-q.wake
+
+		q.mutex.Lock()
+		var pending *syncData
+		if len(q.pending) > 0 {
+			pending = q.pending[0]
+			q.pending = q.pending[1:]
+		}
+		if len(q.pending) > 0 {
+			q.signal()
+		}
+		q.mutex.Unlock()
+
+		if pending != nil {
+			return *pending
+		}
+	}
+}
+
+// addRequester adds a requester, merging them with anyone already asking from
+// the same channel.
+func (d *syncData) addRequester(requester syncRequester) {
+	for r := range d.Requesters {
+		if d.Requesters[r].ChannelId == requester.ChannelId {
+			d.Requesters[r].SendMessage = d.Requesters[r].SendMessage || requester.SendMessage
+			d.Requesters[r].Summary = d.Requesters[r].Summary || requester.Summary
+			return
+		}
+	}
+
+	d.Requesters = append(d.Requesters, requester)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"testing"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+)
+
+func TestSyncOptionsMerge(t *testing.T) {
+	full := syncOptions{Scope: newSyncScope([]string{"1"}, nil)}
+
+	tests := []struct {
+		name      string
+		other     syncOptions
+		mergeable bool
+		users     []string
+		everyone  bool
+	}{
+		{name: "same", other: syncOptions{Scope: newSyncScope([]string{"2"}, nil)},
+			mergeable: true, users: []string{"1", "2"}},
+		{name: "same, everyone", other: syncOptions{},
+			mergeable: true, everyone: true},
+		{name: "add only", other: syncOptions{Scope: full.Scope, Mode: rolesrv.SyncMode_ADD_ONLY}},
+		{name: "remove only", other: syncOptions{Scope: full.Scope, Mode: rolesrv.SyncMode_REMOVE_ONLY}},
+		{name: "preserving unmanaged", other: syncOptions{Scope: full.Scope, PreserveUnmanaged: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if full.mergeable(test.other) != test.mergeable || test.other.mergeable(full) != test.mergeable {
+				t.Fatalf("mergeable isn't %v both ways", test.mergeable)
+			}
+
+			if !test.mergeable {
+				return
+			}
+
+			merged := full.merge(test.other)
+			if merged.Mode != full.Mode || merged.PreserveUnmanaged != full.PreserveUnmanaged {
+				t.Errorf("merge changed the options: %+v", merged)
+			}
+
+			if test.everyone {
+				if merged.Scope != nil {
+					t.Errorf("merged scope isn't everyone: %v", merged.Scope.users.ToSlice())
+				}
+				return
+			}
+
+			checkSet(t, merged.Scope.users, test.users...)
+		})
+	}
+}
+
+func TestSyncQueue(t *testing.T) {
+	syncJobs = &syncJobList{limit: 10}
+	q := newSyncQueue()
+
+	adds := []struct {
+		user    string
+		options syncOptions
+		folded  bool
+	}{
+		{user: "1"},
+		{user: "2", folded: true},
+		{user: "3", options: syncOptions{Mode: rolesrv.SyncMode_ADD_ONLY}},
+		{user: "4", options: syncOptions{PreserveUnmanaged: true}},
+		{user: "5", options: syncOptions{Mode: rolesrv.SyncMode_ADD_ONLY}, folded: true},
+	}
+
+	for _, add := range adds {
+		add.options.Scope = newSyncScope([]string{add.user}, nil)
+		if _, folded := q.add(syncRequester{}, add.options); folded != add.folded {
+			t.Errorf("adding %s folded: %v, want %v", add.user, folded, add.folded)
+		}
+	}
+
+	runs := []struct {
+		options syncOptions
+		users   []string
+	}{
+		{users: []string{"1", "2"}},
+		{options: syncOptions{Mode: rolesrv.SyncMode_ADD_ONLY}, users: []string{"3", "5"}},
+		{options: syncOptions{PreserveUnmanaged: true}, users: []string{"4"}},
+	}
+
+	for _, run := range runs {
+		next := q.next()
+		if !next.syncOptions.mergeable(run.options) {
+			t.Errorf("got %+v, want %+v", next.syncOptions, run.options)
+		}
+		checkSet(t, next.Scope.users, run.users...)
+	}
+
+	if len(q.pending) != 0 {
+		t.Errorf("%d syncs left over", len(q.pending))
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/chremoas/role-srv/storage"
+	"github.com/chremoas/services-common/sets"
+	"go.uber.org/zap"
+)
+
+// newTestHandler is a handler on an empty MemoryStore.
+func newTestHandler() *rolesHandler {
+	return &rolesHandler{Store: storage.NewMemoryStore(), Logger: zap.NewNop()}
+}
+
+// addTestFilter creates a filter with members.
+func addTestFilter(t *testing.T, h *rolesHandler, name string, members ...string) {
+	t.Helper()
+
+	if err := h.Store.CreateFilter(name, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Store.AddFilterMembers(name, members); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addTestRole creates a role with fields.
+func addTestRole(t *testing.T, h *rolesHandler, name string, fields map[string]string) {
+	t.Helper()
+
+	if err := h.Store.CreateRole(name, fields, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newStringSet(members ...string) *sets.StringSet {
+	set := sets.NewStringSet()
+	set.FromSlice(members)
+	return set
+}
+
+// checkSet fails the test unless set holds exactly want.
+func checkSet(t *testing.T, set *sets.StringSet, want ...string) {
+	t.Helper()
+
+	got := set.ToSlice()
+	sort.Strings(got)
+	sort.Strings(want)
+
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	common "github.com/chremoas/services-common/command"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+// Every replica runs the schedule, so each slot is claimed in Redis by the
+// first replica to reach it and the rest skip it. The claim is keyed on the
+// slot's time, which the replicas have to agree on: intervals land on whole
+// multiples of themselves (as time.Truncate counts them) rather than on
+// whenever each replica started.
+
+const scheduleClaimKey = "sync:schedule:"
+
+// scheduleClaimTTL is how long a claimed slot is remembered. It only has to
+// outlast the clock skew between replicas.
+const scheduleClaimTTL = time.Hour
+
+// alignedEvery is a fixed interval whose runs land on multiples of it.
+type alignedEvery time.Duration
+
+func (a alignedEvery) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(a)).Add(time.Duration(a))
+}
+
+// parseSyncSchedule accepts either a Go duration ("6h") or a standard five
+// field cron expression, including the @hourly style descriptors and
+// "@every 6h".
+func parseSyncSchedule(spec string) (cron.Schedule, error) {
+	if interval, err := time.ParseDuration(spec); err == nil {
+		if interval < time.Second {
+			return nil, fmt.Errorf("sync schedule `%s` isn't an interval of at least a second", spec)
+		}
+		return alignedEvery(interval), nil
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return alignedEvery(every.Delay), nil
+	}
+
+	return schedule, err
+}
+
+// scheduleThread queues a full sync on roles.syncSchedule so anything changed
+// by hand on Discord gets put back without waiting for an admin. It's off
+// unless a schedule is set. The outcome of each run is posted to
+// roles.syncChannel, if there is one.
+func (h *rolesHandler) scheduleThread(schedule cron.Schedule) {
+	channel := viper.GetString("roles.syncChannel")
+
+	for {
+		slot := schedule.Next(time.Now())
+		time.Sleep(time.Until(slot))
+
+		claimed, err := h.claimScheduleSlot(slot)
+		if err != nil {
+			h.Logger.Sugar().Errorf("scheduleThread: claiming %s: %s", slot, err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		h.Logger.Info("Queueing scheduled sync")
+		syncControl.add(syncRequester{ChannelId: channel, Summary: channel != ""}, syncOptions{})
+	}
+}
+
+// claimScheduleSlot is true if this replica is the first to run the scheduled
+// sync due at slot.
+func (h *rolesHandler) claimScheduleSlot(slot time.Time) (bool, error) {
+	key := h.Redis.KeyName(scheduleClaimKey + strconv.FormatInt(slot.Unix(), 10))
+	return h.Redis.Client.SetNX(key, syncLeader.owner, scheduleClaimTTL).Result()
+}
+
+// sendSummary tells requesters who only want the outcome how the run went.
+func (h *rolesHandler) sendSummary(request syncData) {
+	job := request.Job.snapshot()
+
+	msg := fmt.Sprintf("Sync finished: updated %d of %d users", job.UsersUpdated, job.UsersToUpdate)
+	if len(job.Errors) > 0 {
+		msg = fmt.Sprintf("Sync finished with %d errors, updated %d of %d users. First error: %s",
+			len(job.Errors), job.UsersUpdated, job.UsersToUpdate, job.Errors[0])
+	}
+
+	for _, r := range request.Requesters {
+		if r.Summary {
+			if len(job.Errors) > 0 {
+				h.sendMessage(context.Background(), r.ChannelId, common.SendError(msg), true)
+			} else {
+				h.sendMessage(context.Background(), r.ChannelId, common.SendSuccess(msg), true)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSyncSchedule(t *testing.T) {
+	from := time.Date(2020, 1, 1, 1, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		next time.Time
+		err  bool
+	}{
+		{name: "duration", spec: "6h", next: time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)},
+		{name: "every", spec: "@every 30m", next: time.Date(2020, 1, 1, 1, 30, 0, 0, time.UTC)},
+		{name: "cron", spec: "0 */6 * * *", next: time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)},
+		{name: "too short", spec: "500ms", err: true},
+		{name: "negative", spec: "-1h", err: true},
+		{name: "garbage", spec: "often", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := parseSyncSchedule(test.spec)
+			if test.err {
+				if err == nil {
+					t.Fatalf("got %v, want an error", schedule)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Replicas started at different times still agree on the slot
+			for _, now := range []time.Time{from, from.Add(time.Minute)} {
+				if next := schedule.Next(now); !next.Equal(test.next) {
+					t.Errorf("next after %s is %s, want %s", now, next, test.next)
+				}
+			}
+		})
+	}
+}
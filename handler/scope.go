@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"strconv"
+	"sync"
+
+	discord "github.com/chremoas/discord-gateway/proto"
+	"github.com/chremoas/services-common/sets"
+	"golang.org/x/net/context"
+)
+
+// syncScope limits a sync to some users and to the members of some roles.
+type syncScope struct {
+	users *sets.StringSet
+	roles *sets.StringSet
+}
+
+// newSyncScope returns nil, a full sync, if nothing was asked for.
+func newSyncScope(users, roles []string) *syncScope {
+	if len(users) == 0 && len(roles) == 0 {
+		return nil
+	}
+
+	scope := &syncScope{users: sets.NewStringSet(), roles: sets.NewStringSet()}
+	scope.users.FromSlice(users)
+	scope.roles.FromSlice(roles)
+	return scope
+}
+
+// merge returns a scope covering both. Either being every user makes the
+// result every user.
+func (s *syncScope) merge(other *syncScope) *syncScope {
+	if s == nil || other == nil {
+		return nil
+	}
+
+	merged := &syncScope{users: sets.NewStringSet(), roles: sets.NewStringSet()}
+	merged.users.FromSlice(s.users.ToSlice())
+	merged.users.FromSlice(other.users.ToSlice())
+	merged.roles.FromSlice(s.roles.ToSlice())
+	merged.roles.FromSlice(other.roles.ToSlice())
+	return merged
+}
+
+// membershipSnapshot is each role's membership as of the last member sync,
+// which is how a role limited sync finds the people who have just left the
+// role. It only lives in memory, so after a restart the first role limited
+// sync runs as a full one.
+type membershipSnapshot struct {
+	mutex sync.Mutex
+	roles map[string]*sets.StringSet
+}
+
+var syncedMembership = &membershipSnapshot{}
+
+func (s *membershipSnapshot) get(role string) (*sets.StringSet, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	members, ok := s.roles[role]
+	return members, ok
+}
+
+// scopeUsers works out which users a limited sync has to look at. It returns
+// nil if one of the roles has never been synced, in which case the only safe
+// thing to do is a full sync.
+func (h *rolesHandler) scopeUsers(scope *syncScope) (*sets.StringSet, error) {
+	users := sets.NewStringSet()
+	users.FromSlice(scope.users.ToSlice())
+
+	if scope.roles.Len() == 0 {
+		return users, nil
+	}
+
+	resolver, err := h.newMembershipResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	for role := range scope.roles.Set {
+		last, ok := syncedMembership.get(role)
+		if !ok {
+			return nil, nil
+		}
+
+		members, err := resolver.membership(role)
+		if err != nil {
+			return nil, err
+		}
+
+		users.FromSlice(last.ToSlice())
+		users.FromSlice(members.ToSlice())
+	}
+
+	return users, nil
+}
+
+// getDiscordMember fetches one guild member. The gateway can only list
+// members, so this asks for the single member after the one just below
+// userId. It returns nil if the user isn't in the guild.
+func getDiscordMember(ctx context.Context, userId string) (*discord.Member, error) {
+	id, err := strconv.ParseUint(userId, 10, 64)
+	if err != nil || id == 0 {
+		return nil, nil
+	}
+
+	members, err := clients.discord.GetAllMembers(ctx, &discord.GetAllMembersRequest{
+		After:         strconv.FormatUint(id-1, 10),
+		NumberPerPage: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(members.Members) == 0 || members.Members[0].User.Id != userId {
+		return nil, nil
+	}
+
+	return members.Members[0], nil
+}
+
+// record saves the membership a member sync just pushed. A full sync replaces
+// the lot. A limited sync only covered some users, so for roles outside its
+// scope the people it found are added to what was there, which can only make
+// a later role limited sync look at more users than it needs to. Roles with
+// nothing recorded yet are left for a full sync to fill in.
+func (s *membershipSnapshot) record(roles map[string]*sets.StringSet, scope *syncScope) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if scope == nil {
+		s.roles = roles
+		return
+	}
+
+	for role, members := range roles {
+		last, ok := s.roles[role]
+		if !ok {
+			continue
+		}
+
+		if scope.roles.Contains(role) {
+			s.roles[role] = members
+			continue
+		}
+
+		merged := sets.NewStringSet()
+		merged.FromSlice(last.ToSlice())
+		merged.FromSlice(members.ToSlice())
+		s.roles[role] = merged
+	}
+}
+
+// getAllDiscordMembers pages through every member of the guild.
+func getAllDiscordMembers(ctx context.Context) ([]*discord.Member, error) {
+	// Discord limit is 1000, should probably make this a config option. -brian
+	var numberPerPage int32 = 1000
+	var memberId = ""
+	var all []*discord.Member
+
+	for {
+		members, err := clients.discord.GetAllMembers(ctx, &discord.GetAllMembersRequest{NumberPerPage: numberPerPage, After: memberId})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(members.Members) == 0 {
+			return all, nil
+		}
+
+		for m := range members.Members {
+			all = append(all, members.Members[m])
+
+			oldNum, _ := strconv.Atoi(members.Members[m].User.Id)
+			newNum, _ := strconv.Atoi(memberId)
+
+			if oldNum > newNum {
+				memberId = members.Members[m].User.Id
+			}
+		}
+	}
+}
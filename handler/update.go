@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	rolesrv "github.com/chremoas/role-srv/proto"
+	"github.com/chremoas/role-srv/storage"
+	"golang.org/x/net/context"
+)
+
+const (
+	maxColor = 0xFFFFFF
+	// discordPermissions is every permission bit Discord defines, 0 to 30.
+	discordPermissions = 0x7FFFFFFF
+	// maxRoleName is the longest role name Discord takes.
+	maxRoleName = 100
+)
+
+// boolKeys are the role keys that hold a bool.
+var boolKeys = []string{"Hoist", "Managed", "Mentionable", "Sync", "JoinApproval", "Waitlist"}
+
+// checkRoleField validates the new value of a role field and returns it the
+// way it's stored, along with any filters it needs to exist.
+func (h *rolesHandler) checkRoleField(name, key, value string) (string, []string, error) {
+	switch {
+	case key == "Name":
+		if len(value) == 0 || len(value) > maxRoleName {
+			return "", nil, fmt.Errorf("Name has to be 1 to %d characters.", maxRoleName)
+		}
+
+	case key == "Color":
+		color, err := strconv.ParseInt(value, 0, 64)
+		if err != nil || color < 0 || color > maxColor {
+			return "", nil, errors.New("Color has to be a number from 0 to 0xFFFFFF.")
+		}
+		return strconv.FormatInt(color, 10), nil, nil
+
+	case key == "Position":
+		position, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || position < 0 {
+			return "", nil, errors.New("Position has to be a number, 0 or more.")
+		}
+		return strconv.FormatInt(position, 10), nil, nil
+
+	case key == "Permissions":
+		permissions, err := strconv.ParseInt(value, 0, 64)
+		if err != nil || permissions < 0 || permissions&^discordPermissions != 0 {
+			return "", nil, errors.New("Permissions has to be a combination of Discord permission bits.")
+		}
+		return strconv.FormatInt(permissions, 10), nil, nil
+
+	case key == "Capacity":
+		capacity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || capacity < 0 {
+			return "", nil, errors.New("Capacity has to be a number, 0 for no limit.")
+		}
+		return strconv.FormatInt(capacity, 10), nil, nil
+
+	case validListItem(key, boolKeys):
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s has to be true or false.", key)
+		}
+		return boolToString(b), nil, nil
+
+	// Clearing the expression puts the role back on FilterA/FilterB
+	case key == "Expression" && len(value) != 0:
+		expression, err := parseExpression(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid Expression: %s", err)
+		}
+		return value, expressionFilters(expression), nil
+
+	// Parents come in comma separated
+	case key == "Parents":
+		parents := roleParents(map[string]string{"Parents": value})
+		if err := h.checkParents(name, parents); err != nil {
+			return "", nil, err
+		}
+		return strings.Join(parents, ","), nil, nil
+	}
+
+	return value, nil, nil
+}
+
+// updateRole validates and sets fields of the role in one go, for UpdateRole
+// and UpdateRoleFields.
+func (h *rolesHandler) updateRole(ctx context.Context, method, name string, fields map[string]string) error {
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := h.authorizeRoleUpdate(ctx, method, name, keys); err != nil {
+		return err
+	}
+
+	var filters []string
+	for _, key := range keys {
+		if !validListItem(key, roleKeys) {
+			return fmt.Errorf("`%s` isn't a valid Role Key.", key)
+		}
+
+		value, keyFilters, err := h.checkRoleField(name, key, fields[key])
+		if err != nil {
+			return err
+		}
+
+		fields[key] = value
+		filters = append(filters, keyFilters...)
+	}
+
+	before, err := h.Store.GetRole(name)
+	if err != nil && err != storage.ErrRoleNotFound {
+		return err
+	}
+
+	err = h.Store.UpdateRole(name, fields, filters)
+
+	var missingFilter *storage.MissingFilterError
+	switch {
+	case err == storage.ErrRoleNotFound:
+		return fmt.Errorf("Role `%s` doesn't exists.", name)
+	case errors.As(err, &missingFilter):
+		return fmt.Errorf("Filter `%s` in Expression doesn't exists.", missingFilter.Name)
+	case err != nil:
+		return err
+	}
+
+	previous := make(map[string]string, len(keys))
+	for _, key := range keys {
+		previous[key] = before[key]
+	}
+	h.audit(actingUser(ctx), method, name, previous, fields)
+
+	// More room may let people off the waitlist
+	if _, ok := fields["Capacity"]; ok {
+		promotions.add(before["FilterB"])
+	}
+
+	return nil
+}
+
+func (h *rolesHandler) UpdateRoleFields(ctx context.Context, request *rolesrv.RoleUpdate, response *rolesrv.Role) error {
+	if request.Role == nil || len(request.Fields) == 0 {
+		return errors.New("Role and Fields are required.")
+	}
+
+	name := request.Role.ShortName
+	values := mapProtobufRoleToRole(request.Role)
+
+	fields := make(map[string]string, len(request.Fields))
+	for _, field := range request.Fields {
+		value, ok := values[field]
+		if !ok {
+			return fmt.Errorf("`%s` isn't a valid Role Key.", field)
+		}
+		fields[field] = value
+	}
+
+	if err := h.updateRole(ctx, "UpdateRoleFields", name, fields); err != nil {
+		return err
+	}
+
+	role, err := h.getRole(name)
+	if err != nil {
+		return err
+	}
+
+	*response = *h.mapRoleToProtobufRole(role)
+	return nil
+}
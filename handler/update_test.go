@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckRoleField(t *testing.T) {
+	h := newTestHandler()
+	addTestRole(t, h, "top", map[string]string{})
+	addTestRole(t, h, "role", map[string]string{})
+
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		filters []string
+		err     bool
+	}{
+		{key: "Name", value: "Role", want: "Role"},
+		{key: "Name", value: "", err: true},
+		{key: "Name", value: strings.Repeat("x", maxRoleName+1), err: true},
+		{key: "Color", value: "0xFF", want: "255"},
+		{key: "Color", value: "0x1000000", err: true},
+		{key: "Color", value: "-1", err: true},
+		{key: "Color", value: "red", err: true},
+		{key: "Position", value: "3", want: "3"},
+		{key: "Position", value: "-1", err: true},
+		{key: "Permissions", value: "0x8", want: "8"},
+		{key: "Permissions", value: "0x80000", want: "524288"},
+		{key: "Permissions", value: "0x7FFFFFFF", want: "2147483647"},
+		{key: "Permissions", value: "0x80000000", err: true},
+		{key: "Capacity", value: "0", want: "0"},
+		{key: "Capacity", value: "ten", err: true},
+		{key: "Hoist", value: "true", want: "1"},
+		{key: "Waitlist", value: "0", want: "0"},
+		{key: "Sync", value: "yes", err: true},
+		{key: "Expression", value: "a | b", want: "a | b", filters: []string{"a", "b"}},
+		{key: "Expression", value: "a |", err: true},
+		{key: "Expression", value: "", want: ""},
+		{key: "Parents", value: " top, ", want: "top"},
+		{key: "Parents", value: "role", err: true},
+		{key: "Parents", value: "nope", err: true},
+		{key: "Description", value: "anything", want: "anything"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key+" "+test.value, func(t *testing.T) {
+			value, filters, err := h.checkRoleField("role", test.key, test.value)
+			if test.err {
+				if err == nil {
+					t.Fatalf("got %q, want an error", value)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if value != test.want {
+				t.Errorf("got %q, want %q", value, test.want)
+			}
+
+			checkSet(t, newStringSet(filters...), test.filters...)
+			if len(test.filters) == 0 && !reflect.DeepEqual(filters, []string(nil)) {
+				t.Errorf("got filters %v, want none", filters)
+			}
+		})
+	}
+}
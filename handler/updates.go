@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	discord "github.com/chremoas/discord-gateway/proto"
+	rolesrv "github.com/chremoas/role-srv/proto"
+	microerrors "github.com/micro/go-micro/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+)
+
+// How syncMembers pushes updates to the gateway, all under roles.*:
+//
+//	syncWorkers  how many UpdateMember calls can be in flight (4)
+//	syncRate     UpdateMember calls per second across all workers (5)
+//	syncRetries  retries after a transient gateway error (3)
+//	syncBackoff  wait before the first retry, doubling after that (1s)
+type memberUpdater struct {
+	workers int
+	limiter *rate.Limiter
+	retries int
+	backoff time.Duration
+}
+
+// maxReportedFailures is how many failed users the end of sync message names.
+const maxReportedFailures = 10
+
+func newMemberUpdater() *memberUpdater {
+	u := &memberUpdater{
+		workers: viper.GetInt("roles.syncWorkers"),
+		retries: viper.GetInt("roles.syncRetries"),
+		backoff: viper.GetDuration("roles.syncBackoff"),
+	}
+
+	if u.workers <= 0 {
+		u.workers = 4
+	}
+	if !viper.IsSet("roles.syncRetries") {
+		u.retries = 3
+	}
+	if u.backoff <= 0 {
+		u.backoff = time.Second
+	}
+
+	perSecond := viper.GetFloat64("roles.syncRate")
+	if perSecond <= 0 {
+		perSecond = 5
+	}
+	u.limiter = rate.NewLimiter(rate.Limit(perSecond), u.workers)
+
+	return u
+}
+
+// isTransient is true for gateway errors worth trying again: timeouts, rate
+// limits, the gateway being unavailable and go-micro failing to reach it.
+func isTransient(err error) bool {
+	e := microerrors.Parse(err.Error())
+
+	switch e.Code {
+	case 0, 408, 429, 502, 503, 504:
+		return true
+	case 500:
+		return e.Id == "go.micro.client"
+	}
+
+	return false
+}
+
+// updateMembers applies every change in the plan, several at a time. Failed
+// users are collected and reported once at the end. It only gives up early if
+// the sync lock is lost.
+func (h *rolesHandler) updateMembers(request syncData, plan *memberPlan) error {
+	sugar := h.Logger.Sugar()
+	updater := newMemberUpdater()
+
+	changes := make(chan *memberChange)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var failures []string
+	var lost error
+
+	for w := 0; w < updater.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for change := range changes {
+				mutex.Lock()
+				stop := lost != nil
+				mutex.Unlock()
+				if stop {
+					continue
+				}
+
+				if err := request.Lease.check(); err != nil {
+					mutex.Lock()
+					lost = err
+					mutex.Unlock()
+					continue
+				}
+
+				// Roles that failed to get created on Discord can't be handed out
+				var roleIds []string
+				for r := range change.roles.Set {
+					if _, ok := plan.roleNames[r]; ok {
+						roleIds = append(roleIds, r)
+					}
+				}
+
+				err := updater.updateMember(change.userId, roleIds)
+				if err != nil {
+					sugar.Errorf("syncMembers: UpdateMember: %s: %s", change.userId, err)
+					request.Job.addError("UpdateMember %s: %s", change.userId, err)
+
+					mutex.Lock()
+					failures = append(failures, fmt.Sprintf("%s (%s)", change.userId, microerrors.Parse(err.Error()).Detail))
+					mutex.Unlock()
+				}
+
+				request.Job.update(func(j *rolesrv.SyncJob) {
+					j.UsersProcessed++
+					if err != nil {
+						j.UsersFailed++
+					} else {
+						j.UsersUpdated++
+					}
+				})
+				sugar.Infof("Updating Discord User: %s", change.userId)
+			}
+		}()
+	}
+
+	for _, change := range plan.changes {
+		changes <- change
+	}
+	close(changes)
+	wg.Wait()
+
+	if len(failures) > 0 {
+		msg := fmt.Sprintf("syncMembers: failed to update %d users", len(failures))
+		if len(failures) > maxReportedFailures {
+			msg += fmt.Sprintf(", the first %d were", maxReportedFailures)
+			failures = failures[:maxReportedFailures]
+		}
+		msg += ": " + strings.Join(failures, ", ")
+
+		h.sendFatal(msg, request)
+	}
+
+	return lost
+}
+
+func (u *memberUpdater) updateMember(userId string, roleIds []string) error {
+	backoff := u.backoff
+
+	for attempt := 0; ; attempt++ {
+		if err := u.limiter.Wait(context.Background()); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+		_, err := clients.discord.UpdateMember(ctx, &discord.UpdateMemberRequest{
+			Operation: discord.MemberUpdateOperation_ADD_OR_UPDATE_ROLES,
+			UserId:    userId,
+			RoleIds:   roleIds,
+		})
+		cancel()
+
+		if err == nil || attempt >= u.retries || !isTransient(err) {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,6 @@
+package chremoas_roles
+
+// UserMetadataKey is the go-micro metadata key callers put the Discord user Id
+// they're acting for under. Every RPC that changes something checks that
+// user's permissions.
+const UserMetadataKey = "X-Chremoas-User"
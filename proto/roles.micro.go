@@ -17,9 +17,29 @@ It has these top-level messages:
 	GetDiscordUserListResponse
 	GetDiscordUserResponse
 	SyncRequest
+	SyncJob
+	SyncLock
+	SyncJobRequest
+	SyncJobList
+	SyncPlan
+	RoleLink
+	RoleEdit
+	FieldChange
+	MemberChange
+	ImportRolesRequest
+	ImportRolesResponse
+	AuditQuery
+	AuditEntry
+	AuditLog
 	StringList
 	Role
+	RoleManagers
+	JoinRequest
+	JoinResult
+	JoinRequestQuery
+	JoinRequestList
 	UpdateInfo
+	RoleUpdate
 	GetRolesResponse
 	FilterList
 	Filter
@@ -59,11 +79,15 @@ var _ server.Option
 type RolesService interface {
 	AddRole(ctx context.Context, in *Role, opts ...client.CallOption) (*NilMessage, error)
 	UpdateRole(ctx context.Context, in *UpdateInfo, opts ...client.CallOption) (*NilMessage, error)
+	UpdateRoleFields(ctx context.Context, in *RoleUpdate, opts ...client.CallOption) (*Role, error)
 	RemoveRole(ctx context.Context, in *Role, opts ...client.CallOption) (*NilMessage, error)
 	GetRoles(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*GetRolesResponse, error)
 	GetRole(ctx context.Context, in *Role, opts ...client.CallOption) (*Role, error)
 	GetRoleKeys(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*StringList, error)
 	GetRoleTypes(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*StringList, error)
+	GetRoleManagers(ctx context.Context, in *Role, opts ...client.CallOption) (*RoleManagers, error)
+	AddRoleManagers(ctx context.Context, in *RoleManagers, opts ...client.CallOption) (*RoleManagers, error)
+	RemoveRoleManagers(ctx context.Context, in *RoleManagers, opts ...client.CallOption) (*RoleManagers, error)
 	GetRoleMembership(ctx context.Context, in *RoleMembershipRequest, opts ...client.CallOption) (*RoleMembershipResponse, error)
 	ListUserRoles(ctx context.Context, in *ListUserRolesRequest, opts ...client.CallOption) (*ListUserRolesResponse, error)
 	GetFilters(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*FilterList, error)
@@ -72,7 +96,20 @@ type RolesService interface {
 	GetMembers(ctx context.Context, in *Filter, opts ...client.CallOption) (*MemberList, error)
 	AddMembers(ctx context.Context, in *Members, opts ...client.CallOption) (*NilMessage, error)
 	RemoveMembers(ctx context.Context, in *Members, opts ...client.CallOption) (*NilMessage, error)
-	SyncToChatService(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*NilMessage, error)
+	JoinSIG(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*JoinResult, error)
+	RequestJoin(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*JoinRequest, error)
+	GetJoinRequests(ctx context.Context, in *JoinRequestQuery, opts ...client.CallOption) (*JoinRequestList, error)
+	ApproveJoinRequest(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error)
+	DenyJoinRequest(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error)
+	GetWaitlist(ctx context.Context, in *Role, opts ...client.CallOption) (*JoinRequestList, error)
+	RemoveFromWaitlist(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error)
+	SyncToChatService(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*SyncJob, error)
+	PlanSync(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*SyncPlan, error)
+	GetSyncJob(ctx context.Context, in *SyncJobRequest, opts ...client.CallOption) (*SyncJob, error)
+	GetSyncHistory(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*SyncJobList, error)
+	GetSyncLock(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*SyncLock, error)
+	ImportRoles(ctx context.Context, in *ImportRolesRequest, opts ...client.CallOption) (*ImportRolesResponse, error)
+	GetAuditLog(ctx context.Context, in *AuditQuery, opts ...client.CallOption) (*AuditLog, error)
 	GetDiscordUser(ctx context.Context, in *GetDiscordUserRequest, opts ...client.CallOption) (*GetDiscordUserResponse, error)
 	GetDiscordUserList(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*GetDiscordUserListResponse, error)
 }
@@ -115,6 +152,16 @@ func (c *rolesService) UpdateRole(ctx context.Context, in *UpdateInfo, opts ...c
 	return out, nil
 }
 
+func (c *rolesService) UpdateRoleFields(ctx context.Context, in *RoleUpdate, opts ...client.CallOption) (*Role, error) {
+	req := c.c.NewRequest(c.name, "Roles.UpdateRoleFields", in)
+	out := new(Role)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *rolesService) RemoveRole(ctx context.Context, in *Role, opts ...client.CallOption) (*NilMessage, error) {
 	req := c.c.NewRequest(c.name, "Roles.RemoveRole", in)
 	out := new(NilMessage)
@@ -165,6 +212,36 @@ func (c *rolesService) GetRoleTypes(ctx context.Context, in *NilMessage, opts ..
 	return out, nil
 }
 
+func (c *rolesService) GetRoleManagers(ctx context.Context, in *Role, opts ...client.CallOption) (*RoleManagers, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetRoleManagers", in)
+	out := new(RoleManagers)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) AddRoleManagers(ctx context.Context, in *RoleManagers, opts ...client.CallOption) (*RoleManagers, error) {
+	req := c.c.NewRequest(c.name, "Roles.AddRoleManagers", in)
+	out := new(RoleManagers)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) RemoveRoleManagers(ctx context.Context, in *RoleManagers, opts ...client.CallOption) (*RoleManagers, error) {
+	req := c.c.NewRequest(c.name, "Roles.RemoveRoleManagers", in)
+	out := new(RoleManagers)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *rolesService) GetRoleMembership(ctx context.Context, in *RoleMembershipRequest, opts ...client.CallOption) (*RoleMembershipResponse, error) {
 	req := c.c.NewRequest(c.name, "Roles.GetRoleMembership", in)
 	out := new(RoleMembershipResponse)
@@ -245,8 +322,48 @@ func (c *rolesService) RemoveMembers(ctx context.Context, in *Members, opts ...c
 	return out, nil
 }
 
-func (c *rolesService) SyncToChatService(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*NilMessage, error) {
-	req := c.c.NewRequest(c.name, "Roles.SyncToChatService", in)
+func (c *rolesService) JoinSIG(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*JoinResult, error) {
+	req := c.c.NewRequest(c.name, "Roles.JoinSIG", in)
+	out := new(JoinResult)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) RequestJoin(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*JoinRequest, error) {
+	req := c.c.NewRequest(c.name, "Roles.RequestJoin", in)
+	out := new(JoinRequest)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) GetJoinRequests(ctx context.Context, in *JoinRequestQuery, opts ...client.CallOption) (*JoinRequestList, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetJoinRequests", in)
+	out := new(JoinRequestList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) ApproveJoinRequest(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error) {
+	req := c.c.NewRequest(c.name, "Roles.ApproveJoinRequest", in)
+	out := new(NilMessage)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) DenyJoinRequest(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error) {
+	req := c.c.NewRequest(c.name, "Roles.DenyJoinRequest", in)
 	out := new(NilMessage)
 	err := c.c.Call(ctx, req, out, opts...)
 	if err != nil {
@@ -255,6 +372,96 @@ func (c *rolesService) SyncToChatService(ctx context.Context, in *SyncRequest, o
 	return out, nil
 }
 
+func (c *rolesService) GetWaitlist(ctx context.Context, in *Role, opts ...client.CallOption) (*JoinRequestList, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetWaitlist", in)
+	out := new(JoinRequestList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) RemoveFromWaitlist(ctx context.Context, in *JoinRequest, opts ...client.CallOption) (*NilMessage, error) {
+	req := c.c.NewRequest(c.name, "Roles.RemoveFromWaitlist", in)
+	out := new(NilMessage)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) SyncToChatService(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*SyncJob, error) {
+	req := c.c.NewRequest(c.name, "Roles.SyncToChatService", in)
+	out := new(SyncJob)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) PlanSync(ctx context.Context, in *SyncRequest, opts ...client.CallOption) (*SyncPlan, error) {
+	req := c.c.NewRequest(c.name, "Roles.PlanSync", in)
+	out := new(SyncPlan)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) GetSyncJob(ctx context.Context, in *SyncJobRequest, opts ...client.CallOption) (*SyncJob, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetSyncJob", in)
+	out := new(SyncJob)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) GetSyncHistory(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*SyncJobList, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetSyncHistory", in)
+	out := new(SyncJobList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) GetSyncLock(ctx context.Context, in *NilMessage, opts ...client.CallOption) (*SyncLock, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetSyncLock", in)
+	out := new(SyncLock)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) ImportRoles(ctx context.Context, in *ImportRolesRequest, opts ...client.CallOption) (*ImportRolesResponse, error) {
+	req := c.c.NewRequest(c.name, "Roles.ImportRoles", in)
+	out := new(ImportRolesResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rolesService) GetAuditLog(ctx context.Context, in *AuditQuery, opts ...client.CallOption) (*AuditLog, error) {
+	req := c.c.NewRequest(c.name, "Roles.GetAuditLog", in)
+	out := new(AuditLog)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *rolesService) GetDiscordUser(ctx context.Context, in *GetDiscordUserRequest, opts ...client.CallOption) (*GetDiscordUserResponse, error) {
 	req := c.c.NewRequest(c.name, "Roles.GetDiscordUser", in)
 	out := new(GetDiscordUserResponse)
@@ -280,11 +487,15 @@ func (c *rolesService) GetDiscordUserList(ctx context.Context, in *NilMessage, o
 type RolesHandler interface {
 	AddRole(context.Context, *Role, *NilMessage) error
 	UpdateRole(context.Context, *UpdateInfo, *NilMessage) error
+	UpdateRoleFields(context.Context, *RoleUpdate, *Role) error
 	RemoveRole(context.Context, *Role, *NilMessage) error
 	GetRoles(context.Context, *NilMessage, *GetRolesResponse) error
 	GetRole(context.Context, *Role, *Role) error
 	GetRoleKeys(context.Context, *NilMessage, *StringList) error
 	GetRoleTypes(context.Context, *NilMessage, *StringList) error
+	GetRoleManagers(context.Context, *Role, *RoleManagers) error
+	AddRoleManagers(context.Context, *RoleManagers, *RoleManagers) error
+	RemoveRoleManagers(context.Context, *RoleManagers, *RoleManagers) error
 	GetRoleMembership(context.Context, *RoleMembershipRequest, *RoleMembershipResponse) error
 	ListUserRoles(context.Context, *ListUserRolesRequest, *ListUserRolesResponse) error
 	GetFilters(context.Context, *NilMessage, *FilterList) error
@@ -293,7 +504,20 @@ type RolesHandler interface {
 	GetMembers(context.Context, *Filter, *MemberList) error
 	AddMembers(context.Context, *Members, *NilMessage) error
 	RemoveMembers(context.Context, *Members, *NilMessage) error
-	SyncToChatService(context.Context, *SyncRequest, *NilMessage) error
+	JoinSIG(context.Context, *JoinRequest, *JoinResult) error
+	RequestJoin(context.Context, *JoinRequest, *JoinRequest) error
+	GetJoinRequests(context.Context, *JoinRequestQuery, *JoinRequestList) error
+	ApproveJoinRequest(context.Context, *JoinRequest, *NilMessage) error
+	DenyJoinRequest(context.Context, *JoinRequest, *NilMessage) error
+	GetWaitlist(context.Context, *Role, *JoinRequestList) error
+	RemoveFromWaitlist(context.Context, *JoinRequest, *NilMessage) error
+	SyncToChatService(context.Context, *SyncRequest, *SyncJob) error
+	PlanSync(context.Context, *SyncRequest, *SyncPlan) error
+	GetSyncJob(context.Context, *SyncJobRequest, *SyncJob) error
+	GetSyncHistory(context.Context, *NilMessage, *SyncJobList) error
+	GetSyncLock(context.Context, *NilMessage, *SyncLock) error
+	ImportRoles(context.Context, *ImportRolesRequest, *ImportRolesResponse) error
+	GetAuditLog(context.Context, *AuditQuery, *AuditLog) error
 	GetDiscordUser(context.Context, *GetDiscordUserRequest, *GetDiscordUserResponse) error
 	GetDiscordUserList(context.Context, *NilMessage, *GetDiscordUserListResponse) error
 }
@@ -302,11 +526,15 @@ func RegisterRolesHandler(s server.Server, hdlr RolesHandler, opts ...server.Han
 	type roles interface {
 		AddRole(ctx context.Context, in *Role, out *NilMessage) error
 		UpdateRole(ctx context.Context, in *UpdateInfo, out *NilMessage) error
+		UpdateRoleFields(ctx context.Context, in *RoleUpdate, out *Role) error
 		RemoveRole(ctx context.Context, in *Role, out *NilMessage) error
 		GetRoles(ctx context.Context, in *NilMessage, out *GetRolesResponse) error
 		GetRole(ctx context.Context, in *Role, out *Role) error
 		GetRoleKeys(ctx context.Context, in *NilMessage, out *StringList) error
 		GetRoleTypes(ctx context.Context, in *NilMessage, out *StringList) error
+		GetRoleManagers(ctx context.Context, in *Role, out *RoleManagers) error
+		AddRoleManagers(ctx context.Context, in *RoleManagers, out *RoleManagers) error
+		RemoveRoleManagers(ctx context.Context, in *RoleManagers, out *RoleManagers) error
 		GetRoleMembership(ctx context.Context, in *RoleMembershipRequest, out *RoleMembershipResponse) error
 		ListUserRoles(ctx context.Context, in *ListUserRolesRequest, out *ListUserRolesResponse) error
 		GetFilters(ctx context.Context, in *NilMessage, out *FilterList) error
@@ -315,7 +543,20 @@ func RegisterRolesHandler(s server.Server, hdlr RolesHandler, opts ...server.Han
 		GetMembers(ctx context.Context, in *Filter, out *MemberList) error
 		AddMembers(ctx context.Context, in *Members, out *NilMessage) error
 		RemoveMembers(ctx context.Context, in *Members, out *NilMessage) error
-		SyncToChatService(ctx context.Context, in *SyncRequest, out *NilMessage) error
+		JoinSIG(ctx context.Context, in *JoinRequest, out *JoinResult) error
+		RequestJoin(ctx context.Context, in *JoinRequest, out *JoinRequest) error
+		GetJoinRequests(ctx context.Context, in *JoinRequestQuery, out *JoinRequestList) error
+		ApproveJoinRequest(ctx context.Context, in *JoinRequest, out *NilMessage) error
+		DenyJoinRequest(ctx context.Context, in *JoinRequest, out *NilMessage) error
+		GetWaitlist(ctx context.Context, in *Role, out *JoinRequestList) error
+		RemoveFromWaitlist(ctx context.Context, in *JoinRequest, out *NilMessage) error
+		SyncToChatService(ctx context.Context, in *SyncRequest, out *SyncJob) error
+		PlanSync(ctx context.Context, in *SyncRequest, out *SyncPlan) error
+		GetSyncJob(ctx context.Context, in *SyncJobRequest, out *SyncJob) error
+		GetSyncHistory(ctx context.Context, in *NilMessage, out *SyncJobList) error
+		GetSyncLock(ctx context.Context, in *NilMessage, out *SyncLock) error
+		ImportRoles(ctx context.Context, in *ImportRolesRequest, out *ImportRolesResponse) error
+		GetAuditLog(ctx context.Context, in *AuditQuery, out *AuditLog) error
 		GetDiscordUser(ctx context.Context, in *GetDiscordUserRequest, out *GetDiscordUserResponse) error
 		GetDiscordUserList(ctx context.Context, in *NilMessage, out *GetDiscordUserListResponse) error
 	}
@@ -338,6 +579,10 @@ func (h *rolesHandler) UpdateRole(ctx context.Context, in *UpdateInfo, out *NilM
 	return h.RolesHandler.UpdateRole(ctx, in, out)
 }
 
+func (h *rolesHandler) UpdateRoleFields(ctx context.Context, in *RoleUpdate, out *Role) error {
+	return h.RolesHandler.UpdateRoleFields(ctx, in, out)
+}
+
 func (h *rolesHandler) RemoveRole(ctx context.Context, in *Role, out *NilMessage) error {
 	return h.RolesHandler.RemoveRole(ctx, in, out)
 }
@@ -358,6 +603,18 @@ func (h *rolesHandler) GetRoleTypes(ctx context.Context, in *NilMessage, out *St
 	return h.RolesHandler.GetRoleTypes(ctx, in, out)
 }
 
+func (h *rolesHandler) GetRoleManagers(ctx context.Context, in *Role, out *RoleManagers) error {
+	return h.RolesHandler.GetRoleManagers(ctx, in, out)
+}
+
+func (h *rolesHandler) AddRoleManagers(ctx context.Context, in *RoleManagers, out *RoleManagers) error {
+	return h.RolesHandler.AddRoleManagers(ctx, in, out)
+}
+
+func (h *rolesHandler) RemoveRoleManagers(ctx context.Context, in *RoleManagers, out *RoleManagers) error {
+	return h.RolesHandler.RemoveRoleManagers(ctx, in, out)
+}
+
 func (h *rolesHandler) GetRoleMembership(ctx context.Context, in *RoleMembershipRequest, out *RoleMembershipResponse) error {
 	return h.RolesHandler.GetRoleMembership(ctx, in, out)
 }
@@ -390,10 +647,62 @@ func (h *rolesHandler) RemoveMembers(ctx context.Context, in *Members, out *NilM
 	return h.RolesHandler.RemoveMembers(ctx, in, out)
 }
 
-func (h *rolesHandler) SyncToChatService(ctx context.Context, in *SyncRequest, out *NilMessage) error {
+func (h *rolesHandler) JoinSIG(ctx context.Context, in *JoinRequest, out *JoinResult) error {
+	return h.RolesHandler.JoinSIG(ctx, in, out)
+}
+
+func (h *rolesHandler) RequestJoin(ctx context.Context, in *JoinRequest, out *JoinRequest) error {
+	return h.RolesHandler.RequestJoin(ctx, in, out)
+}
+
+func (h *rolesHandler) GetJoinRequests(ctx context.Context, in *JoinRequestQuery, out *JoinRequestList) error {
+	return h.RolesHandler.GetJoinRequests(ctx, in, out)
+}
+
+func (h *rolesHandler) ApproveJoinRequest(ctx context.Context, in *JoinRequest, out *NilMessage) error {
+	return h.RolesHandler.ApproveJoinRequest(ctx, in, out)
+}
+
+func (h *rolesHandler) DenyJoinRequest(ctx context.Context, in *JoinRequest, out *NilMessage) error {
+	return h.RolesHandler.DenyJoinRequest(ctx, in, out)
+}
+
+func (h *rolesHandler) GetWaitlist(ctx context.Context, in *Role, out *JoinRequestList) error {
+	return h.RolesHandler.GetWaitlist(ctx, in, out)
+}
+
+func (h *rolesHandler) RemoveFromWaitlist(ctx context.Context, in *JoinRequest, out *NilMessage) error {
+	return h.RolesHandler.RemoveFromWaitlist(ctx, in, out)
+}
+
+func (h *rolesHandler) SyncToChatService(ctx context.Context, in *SyncRequest, out *SyncJob) error {
 	return h.RolesHandler.SyncToChatService(ctx, in, out)
 }
 
+func (h *rolesHandler) PlanSync(ctx context.Context, in *SyncRequest, out *SyncPlan) error {
+	return h.RolesHandler.PlanSync(ctx, in, out)
+}
+
+func (h *rolesHandler) GetSyncJob(ctx context.Context, in *SyncJobRequest, out *SyncJob) error {
+	return h.RolesHandler.GetSyncJob(ctx, in, out)
+}
+
+func (h *rolesHandler) GetSyncHistory(ctx context.Context, in *NilMessage, out *SyncJobList) error {
+	return h.RolesHandler.GetSyncHistory(ctx, in, out)
+}
+
+func (h *rolesHandler) GetSyncLock(ctx context.Context, in *NilMessage, out *SyncLock) error {
+	return h.RolesHandler.GetSyncLock(ctx, in, out)
+}
+
+func (h *rolesHandler) ImportRoles(ctx context.Context, in *ImportRolesRequest, out *ImportRolesResponse) error {
+	return h.RolesHandler.ImportRoles(ctx, in, out)
+}
+
+func (h *rolesHandler) GetAuditLog(ctx context.Context, in *AuditQuery, out *AuditLog) error {
+	return h.RolesHandler.GetAuditLog(ctx, in, out)
+}
+
 func (h *rolesHandler) GetDiscordUser(ctx context.Context, in *GetDiscordUserRequest, out *GetDiscordUserResponse) error {
 	return h.RolesHandler.GetDiscordUser(ctx, in, out)
 }
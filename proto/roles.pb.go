@@ -17,9 +17,29 @@ It has these top-level messages:
 	GetDiscordUserListResponse
 	GetDiscordUserResponse
 	SyncRequest
+	SyncJob
+	SyncLock
+	SyncJobRequest
+	SyncJobList
+	SyncPlan
+	RoleLink
+	RoleEdit
+	FieldChange
+	MemberChange
+	ImportRolesRequest
+	ImportRolesResponse
+	AuditQuery
+	AuditEntry
+	AuditLog
 	StringList
 	Role
+	RoleManagers
+	JoinRequest
+	JoinResult
+	JoinRequestQuery
+	JoinRequestList
 	UpdateInfo
+	RoleUpdate
 	GetRolesResponse
 	FilterList
 	Filter
@@ -43,6 +63,84 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type SyncMode int32
+
+const (
+	// Give everyone exactly the roles Chremoas says they should have.
+	SyncMode_FULL SyncMode = 0
+	// Only hand out missing roles, never take any away.
+	SyncMode_ADD_ONLY SyncMode = 1
+	// Only take away roles people shouldn't have, never hand any out.
+	SyncMode_REMOVE_ONLY SyncMode = 2
+)
+
+var SyncMode_name = map[int32]string{
+	0: "FULL",
+	1: "ADD_ONLY",
+	2: "REMOVE_ONLY",
+}
+var SyncMode_value = map[string]int32{
+	"FULL":        0,
+	"ADD_ONLY":    1,
+	"REMOVE_ONLY": 2,
+}
+
+func (x SyncMode) String() string {
+	return proto.EnumName(SyncMode_name, int32(x))
+}
+func (SyncMode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type SyncJobState int32
+
+const (
+	SyncJobState_QUEUED    SyncJobState = 0
+	SyncJobState_RUNNING   SyncJobState = 1
+	SyncJobState_SUCCEEDED SyncJobState = 2
+	SyncJobState_FAILED    SyncJobState = 3
+)
+
+var SyncJobState_name = map[int32]string{
+	0: "QUEUED",
+	1: "RUNNING",
+	2: "SUCCEEDED",
+	3: "FAILED",
+}
+var SyncJobState_value = map[string]int32{
+	"QUEUED":    0,
+	"RUNNING":   1,
+	"SUCCEEDED": 2,
+	"FAILED":    3,
+}
+
+func (x SyncJobState) String() string {
+	return proto.EnumName(SyncJobState_name, int32(x))
+}
+func (SyncJobState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type JoinState int32
+
+const (
+	JoinState_JOINED     JoinState = 0
+	JoinState_WAITLISTED JoinState = 1
+	JoinState_REQUESTED  JoinState = 2
+)
+
+var JoinState_name = map[int32]string{
+	0: "JOINED",
+	1: "WAITLISTED",
+	2: "REQUESTED",
+}
+var JoinState_value = map[string]int32{
+	"JOINED":     0,
+	"WAITLISTED": 1,
+	"REQUESTED":  2,
+}
+
+func (x JoinState) String() string {
+	return proto.EnumName(JoinState_name, int32(x))
+}
+func (JoinState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
 type NilMessage struct {
 }
 
@@ -231,6 +329,18 @@ type SyncRequest struct {
 	ChannelId   string `protobuf:"bytes,1,opt,name=ChannelId" json:"ChannelId,omitempty"`
 	UserId      string `protobuf:"bytes,2,opt,name=UserId" json:"UserId,omitempty"`
 	SendMessage bool   `protobuf:"varint,3,opt,name=SendMessage" json:"SendMessage,omitempty"`
+	// Limit the sync to these Discord users and to the members (current and
+	// last synced) of these roles, by ShortName. Leave both empty for a full
+	// sync. A limited sync only updates members; it doesn't touch the roles
+	// themselves.
+	UserIds []string `protobuf:"bytes,4,rep,name=UserIds" json:"UserIds,omitempty"`
+	Roles   []string `protobuf:"bytes,5,rep,name=Roles" json:"Roles,omitempty"`
+	// What the member sync is allowed to do to each user's roles. Roles
+	// themselves are synced the same way in every mode.
+	Mode SyncMode `protobuf:"varint,6,opt,name=Mode,enum=chremoas.roles.SyncMode" json:"Mode,omitempty"`
+	// Leave Discord roles that Chremoas doesn't sync members for alone, rather
+	// than taking them off people.
+	PreserveUnmanaged bool `protobuf:"varint,7,opt,name=PreserveUnmanaged" json:"PreserveUnmanaged,omitempty"`
 }
 
 func (m *SyncRequest) Reset()                    { *m = SyncRequest{} }
@@ -259,6 +369,600 @@ func (m *SyncRequest) GetSendMessage() bool {
 	return false
 }
 
+func (m *SyncRequest) GetUserIds() []string {
+	if m != nil {
+		return m.UserIds
+	}
+	return nil
+}
+
+func (m *SyncRequest) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *SyncRequest) GetMode() SyncMode {
+	if m != nil {
+		return m.Mode
+	}
+	return SyncMode_FULL
+}
+
+func (m *SyncRequest) GetPreserveUnmanaged() bool {
+	if m != nil {
+		return m.PreserveUnmanaged
+	}
+	return false
+}
+
+// SyncJob is one run of SyncToChatService. Times are unix seconds.
+type SyncJob struct {
+	Id        string       `protobuf:"bytes,1,opt,name=Id" json:"Id,omitempty"`
+	State     SyncJobState `protobuf:"varint,2,opt,name=State,enum=chremoas.roles.SyncJobState" json:"State,omitempty"`
+	ChannelId string       `protobuf:"bytes,3,opt,name=ChannelId" json:"ChannelId,omitempty"`
+	UserId    string       `protobuf:"bytes,4,opt,name=UserId" json:"UserId,omitempty"`
+	Queued    int64        `protobuf:"varint,5,opt,name=Queued" json:"Queued,omitempty"`
+	Started   int64        `protobuf:"varint,6,opt,name=Started" json:"Started,omitempty"`
+	Finished  int64        `protobuf:"varint,7,opt,name=Finished" json:"Finished,omitempty"`
+	// Users the member sync found out of step with Discord, and how many of
+	// those it has got through so far.
+	UsersToUpdate  int32    `protobuf:"varint,8,opt,name=UsersToUpdate" json:"UsersToUpdate,omitempty"`
+	UsersProcessed int32    `protobuf:"varint,9,opt,name=UsersProcessed" json:"UsersProcessed,omitempty"`
+	UsersUpdated   int32    `protobuf:"varint,10,opt,name=UsersUpdated" json:"UsersUpdated,omitempty"`
+	UsersFailed    int32    `protobuf:"varint,11,opt,name=UsersFailed" json:"UsersFailed,omitempty"`
+	Errors         []string `protobuf:"bytes,12,rep,name=Errors" json:"Errors,omitempty"`
+	// How many SyncToChatService calls were folded into this run.
+	Requests int32 `protobuf:"varint,13,opt,name=Requests" json:"Requests,omitempty"`
+	// Set on the SyncToChatService response when the request joined a sync
+	// that was already waiting to run.
+	AlreadyQueued bool `protobuf:"varint,14,opt,name=AlreadyQueued" json:"AlreadyQueued,omitempty"`
+}
+
+func (m *SyncJob) Reset()                    { *m = SyncJob{} }
+func (m *SyncJob) String() string            { return proto.CompactTextString(m) }
+func (*SyncJob) ProtoMessage()               {}
+func (*SyncJob) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *SyncJob) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SyncJob) GetState() SyncJobState {
+	if m != nil {
+		return m.State
+	}
+	return SyncJobState_QUEUED
+}
+
+func (m *SyncJob) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *SyncJob) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SyncJob) GetQueued() int64 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+func (m *SyncJob) GetStarted() int64 {
+	if m != nil {
+		return m.Started
+	}
+	return 0
+}
+
+func (m *SyncJob) GetFinished() int64 {
+	if m != nil {
+		return m.Finished
+	}
+	return 0
+}
+
+func (m *SyncJob) GetUsersToUpdate() int32 {
+	if m != nil {
+		return m.UsersToUpdate
+	}
+	return 0
+}
+
+func (m *SyncJob) GetUsersProcessed() int32 {
+	if m != nil {
+		return m.UsersProcessed
+	}
+	return 0
+}
+
+func (m *SyncJob) GetUsersUpdated() int32 {
+	if m != nil {
+		return m.UsersUpdated
+	}
+	return 0
+}
+
+func (m *SyncJob) GetUsersFailed() int32 {
+	if m != nil {
+		return m.UsersFailed
+	}
+	return 0
+}
+
+func (m *SyncJob) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *SyncJob) GetRequests() int32 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *SyncJob) GetAlreadyQueued() bool {
+	if m != nil {
+		return m.AlreadyQueued
+	}
+	return false
+}
+
+// SyncLock is the lease a replica has to hold to run a sync. Holder is empty
+// when nobody has it.
+type SyncLock struct {
+	Holder string `protobuf:"bytes,1,opt,name=Holder" json:"Holder,omitempty"`
+	// Fencing token of the current (or last) hold, it goes up by one every
+	// time the lock changes hands.
+	Fence int64 `protobuf:"varint,2,opt,name=Fence" json:"Fence,omitempty"`
+	// Unix time in milliseconds the lease runs out unless it's renewed.
+	Expires int64 `protobuf:"varint,3,opt,name=Expires" json:"Expires,omitempty"`
+}
+
+func (m *SyncLock) Reset()                    { *m = SyncLock{} }
+func (m *SyncLock) String() string            { return proto.CompactTextString(m) }
+func (*SyncLock) ProtoMessage()               {}
+func (*SyncLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *SyncLock) GetHolder() string {
+	if m != nil {
+		return m.Holder
+	}
+	return ""
+}
+
+func (m *SyncLock) GetFence() int64 {
+	if m != nil {
+		return m.Fence
+	}
+	return 0
+}
+
+func (m *SyncLock) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type SyncJobRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=Id" json:"Id,omitempty"`
+}
+
+func (m *SyncJobRequest) Reset()                    { *m = SyncJobRequest{} }
+func (m *SyncJobRequest) String() string            { return proto.CompactTextString(m) }
+func (*SyncJobRequest) ProtoMessage()               {}
+func (*SyncJobRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *SyncJobRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// SyncJobList is newest first.
+type SyncJobList struct {
+	Jobs []*SyncJob `protobuf:"bytes,1,rep,name=Jobs" json:"Jobs,omitempty"`
+}
+
+func (m *SyncJobList) Reset()                    { *m = SyncJobList{} }
+func (m *SyncJobList) String() string            { return proto.CompactTextString(m) }
+func (*SyncJobList) ProtoMessage()               {}
+func (*SyncJobList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *SyncJobList) GetJobs() []*SyncJob {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+// SyncPlan is what SyncToChatService would change on Discord right now.
+type SyncPlan struct {
+	CreateRoles []string        `protobuf:"bytes,1,rep,name=CreateRoles" json:"CreateRoles,omitempty"`
+	DeleteRoles []string        `protobuf:"bytes,2,rep,name=DeleteRoles" json:"DeleteRoles,omitempty"`
+	EditRoles   []*RoleEdit     `protobuf:"bytes,3,rep,name=EditRoles" json:"EditRoles,omitempty"`
+	Members     []*MemberChange `protobuf:"bytes,4,rep,name=Members" json:"Members,omitempty"`
+	// Discord role names more than one role has, which the sync leaves alone.
+	SkipRoles []string `protobuf:"bytes,5,rep,name=SkipRoles" json:"SkipRoles,omitempty"`
+	// Roles matched to their Discord role by name, whose DiscordId the sync
+	// will save.
+	LinkRoles []*RoleLink `protobuf:"bytes,6,rep,name=LinkRoles" json:"LinkRoles,omitempty"`
+}
+
+func (m *SyncPlan) Reset()                    { *m = SyncPlan{} }
+func (m *SyncPlan) String() string            { return proto.CompactTextString(m) }
+func (*SyncPlan) ProtoMessage()               {}
+func (*SyncPlan) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *SyncPlan) GetCreateRoles() []string {
+	if m != nil {
+		return m.CreateRoles
+	}
+	return nil
+}
+
+func (m *SyncPlan) GetDeleteRoles() []string {
+	if m != nil {
+		return m.DeleteRoles
+	}
+	return nil
+}
+
+func (m *SyncPlan) GetEditRoles() []*RoleEdit {
+	if m != nil {
+		return m.EditRoles
+	}
+	return nil
+}
+
+func (m *SyncPlan) GetMembers() []*MemberChange {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *SyncPlan) GetSkipRoles() []string {
+	if m != nil {
+		return m.SkipRoles
+	}
+	return nil
+}
+
+func (m *SyncPlan) GetLinkRoles() []*RoleLink {
+	if m != nil {
+		return m.LinkRoles
+	}
+	return nil
+}
+
+type RoleLink struct {
+	ShortName string `protobuf:"bytes,1,opt,name=ShortName" json:"ShortName,omitempty"`
+	DiscordId string `protobuf:"bytes,2,opt,name=DiscordId" json:"DiscordId,omitempty"`
+}
+
+func (m *RoleLink) Reset()                    { *m = RoleLink{} }
+func (m *RoleLink) String() string            { return proto.CompactTextString(m) }
+func (*RoleLink) ProtoMessage()               {}
+func (*RoleLink) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *RoleLink) GetShortName() string {
+	if m != nil {
+		return m.ShortName
+	}
+	return ""
+}
+
+func (m *RoleLink) GetDiscordId() string {
+	if m != nil {
+		return m.DiscordId
+	}
+	return ""
+}
+
+type RoleEdit struct {
+	Name    string         `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
+	Changes []*FieldChange `protobuf:"bytes,2,rep,name=Changes" json:"Changes,omitempty"`
+}
+
+func (m *RoleEdit) Reset()                    { *m = RoleEdit{} }
+func (m *RoleEdit) String() string            { return proto.CompactTextString(m) }
+func (*RoleEdit) ProtoMessage()               {}
+func (*RoleEdit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RoleEdit) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RoleEdit) GetChanges() []*FieldChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type FieldChange struct {
+	Field string `protobuf:"bytes,1,opt,name=Field" json:"Field,omitempty"`
+	From  string `protobuf:"bytes,2,opt,name=From" json:"From,omitempty"`
+	To    string `protobuf:"bytes,3,opt,name=To" json:"To,omitempty"`
+}
+
+func (m *FieldChange) Reset()                    { *m = FieldChange{} }
+func (m *FieldChange) String() string            { return proto.CompactTextString(m) }
+func (*FieldChange) ProtoMessage()               {}
+func (*FieldChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *FieldChange) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *FieldChange) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *FieldChange) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+// MemberChange is the roles (by name) a user would gain and lose.
+type MemberChange struct {
+	UserId   string   `protobuf:"bytes,1,opt,name=UserId" json:"UserId,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=Username" json:"Username,omitempty"`
+	Add      []string `protobuf:"bytes,3,rep,name=Add" json:"Add,omitempty"`
+	Remove   []string `protobuf:"bytes,4,rep,name=Remove" json:"Remove,omitempty"`
+}
+
+func (m *MemberChange) Reset()                    { *m = MemberChange{} }
+func (m *MemberChange) String() string            { return proto.CompactTextString(m) }
+func (*MemberChange) ProtoMessage()               {}
+func (*MemberChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *MemberChange) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *MemberChange) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *MemberChange) GetAdd() []string {
+	if m != nil {
+		return m.Add
+	}
+	return nil
+}
+
+func (m *MemberChange) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+type ImportRolesRequest struct {
+	// Discord role names to import, or every role Chremoas doesn't already
+	// have if empty.
+	Names []string `protobuf:"bytes,1,rep,name=Names" json:"Names,omitempty"`
+	// Work out what would be imported without creating anything.
+	DryRun bool `protobuf:"varint,2,opt,name=DryRun" json:"DryRun,omitempty"`
+}
+
+func (m *ImportRolesRequest) Reset()                    { *m = ImportRolesRequest{} }
+func (m *ImportRolesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportRolesRequest) ProtoMessage()               {}
+func (*ImportRolesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *ImportRolesRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *ImportRolesRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type ImportRolesResponse struct {
+	// The roles created, each with a filter of the same name holding the
+	// role's current members.
+	Imported []*Role `protobuf:"bytes,1,rep,name=Imported" json:"Imported,omitempty"`
+	// Discord roles left alone, and why.
+	Skipped []string `protobuf:"bytes,2,rep,name=Skipped" json:"Skipped,omitempty"`
+}
+
+func (m *ImportRolesResponse) Reset()                    { *m = ImportRolesResponse{} }
+func (m *ImportRolesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportRolesResponse) ProtoMessage()               {}
+func (*ImportRolesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *ImportRolesResponse) GetImported() []*Role {
+	if m != nil {
+		return m.Imported
+	}
+	return nil
+}
+
+func (m *ImportRolesResponse) GetSkipped() []string {
+	if m != nil {
+		return m.Skipped
+	}
+	return nil
+}
+
+// AuditQuery picks entries out of the audit log. Empty fields match
+// everything. From and To are unix times, both inclusive; To of 0 is now.
+type AuditQuery struct {
+	Actor  string `protobuf:"bytes,1,opt,name=Actor" json:"Actor,omitempty"`
+	Target string `protobuf:"bytes,2,opt,name=Target" json:"Target,omitempty"`
+	From   int64  `protobuf:"varint,3,opt,name=From" json:"From,omitempty"`
+	To     int64  `protobuf:"varint,4,opt,name=To" json:"To,omitempty"`
+	// At most this many entries, 100 if 0.
+	Limit int32 `protobuf:"varint,5,opt,name=Limit" json:"Limit,omitempty"`
+}
+
+func (m *AuditQuery) Reset()                    { *m = AuditQuery{} }
+func (m *AuditQuery) String() string            { return proto.CompactTextString(m) }
+func (*AuditQuery) ProtoMessage()               {}
+func (*AuditQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *AuditQuery) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *AuditQuery) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *AuditQuery) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// AuditEntry is one change: who (the Discord user Id) did it, through which
+// RPC, to which role, filter or sync job, and the values before and after as
+// JSON.
+type AuditEntry struct {
+	Id     int64  `protobuf:"varint,1,opt,name=Id" json:"Id,omitempty"`
+	Time   int64  `protobuf:"varint,2,opt,name=Time" json:"Time,omitempty"`
+	Actor  string `protobuf:"bytes,3,opt,name=Actor" json:"Actor,omitempty"`
+	Method string `protobuf:"bytes,4,opt,name=Method" json:"Method,omitempty"`
+	Target string `protobuf:"bytes,5,opt,name=Target" json:"Target,omitempty"`
+	Before string `protobuf:"bytes,6,opt,name=Before" json:"Before,omitempty"`
+	After  string `protobuf:"bytes,7,opt,name=After" json:"After,omitempty"`
+}
+
+func (m *AuditEntry) Reset()                    { *m = AuditEntry{} }
+func (m *AuditEntry) String() string            { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()               {}
+func (*AuditEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *AuditEntry) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+// AuditLog is newest first.
+type AuditLog struct {
+	Entries []*AuditEntry `protobuf:"bytes,1,rep,name=Entries" json:"Entries,omitempty"`
+}
+
+func (m *AuditLog) Reset()                    { *m = AuditLog{} }
+func (m *AuditLog) String() string            { return proto.CompactTextString(m) }
+func (*AuditLog) ProtoMessage()               {}
+func (*AuditLog) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *AuditLog) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 type StringList struct {
 	Value []string `protobuf:"bytes,1,rep,name=Value" json:"Value,omitempty"`
 }
@@ -266,7 +970,7 @@ type StringList struct {
 func (m *StringList) Reset()                    { *m = StringList{} }
 func (m *StringList) String() string            { return proto.CompactTextString(m) }
 func (*StringList) ProtoMessage()               {}
-func (*StringList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*StringList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *StringList) GetValue() []string {
 	if m != nil {
@@ -283,6 +987,24 @@ type Role struct {
 	Sig       bool   `protobuf:"varint,5,opt,name=Sig" json:"Sig,omitempty"`
 	Joinable  bool   `protobuf:"varint,6,opt,name=Joinable" json:"Joinable,omitempty"`
 	Sync      bool   `protobuf:"varint,7,opt,name=Sync" json:"Sync,omitempty"`
+	// Membership as a set expression over filters, e.g.
+	// "(pilots | alts) & capital_trained - banned". Overrides FilterA/FilterB.
+	Expression string `protobuf:"bytes,8,opt,name=Expression" json:"Expression,omitempty"`
+	// Roles this one counts towards. Members of this role are members of
+	// every parent, and of their parents, and so on.
+	Parents []string `protobuf:"bytes,9,rep,name=Parents" json:"Parents,omitempty"`
+	// Users, and members of ManagerFilter, who may change the members of this
+	// role's filters and its name, color, hoist, position and mentionable
+	// without being role or SIG admins.
+	Managers      []string `protobuf:"bytes,10,rep,name=Managers" json:"Managers,omitempty"`
+	ManagerFilter string   `protobuf:"bytes,11,opt,name=ManagerFilter" json:"ManagerFilter,omitempty"`
+	// Joining this SIG creates a request its managers approve or deny,
+	// unless it's Joinable.
+	JoinApproval bool `protobuf:"varint,12,opt,name=JoinApproval" json:"JoinApproval,omitempty"`
+	// The most members the role can have, 0 for no limit. Joining a full SIG
+	// is refused, or puts you on its waitlist if Waitlist is set.
+	Capacity int32 `protobuf:"varint,13,opt,name=Capacity" json:"Capacity,omitempty"`
+	Waitlist bool  `protobuf:"varint,14,opt,name=Waitlist" json:"Waitlist,omitempty"`
 	// Discord
 	Name        string `protobuf:"bytes,20,opt,name=Name" json:"Name,omitempty"`
 	Color       int32  `protobuf:"varint,21,opt,name=Color" json:"Color,omitempty"`
@@ -291,12 +1013,14 @@ type Role struct {
 	Permissions int32  `protobuf:"varint,24,opt,name=Permissions" json:"Permissions,omitempty"`
 	Managed     bool   `protobuf:"varint,25,opt,name=Managed" json:"Managed,omitempty"`
 	Mentionable bool   `protobuf:"varint,26,opt,name=Mentionable" json:"Mentionable,omitempty"`
+	// Id of the Discord role this one is synced to, set by the sync.
+	DiscordId string `protobuf:"bytes,27,opt,name=DiscordId" json:"DiscordId,omitempty"`
 }
 
 func (m *Role) Reset()                    { *m = Role{} }
 func (m *Role) String() string            { return proto.CompactTextString(m) }
 func (*Role) ProtoMessage()               {}
-func (*Role) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*Role) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *Role) GetType() string {
 	if m != nil {
@@ -347,6 +1071,55 @@ func (m *Role) GetSync() bool {
 	return false
 }
 
+func (m *Role) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+func (m *Role) GetParents() []string {
+	if m != nil {
+		return m.Parents
+	}
+	return nil
+}
+
+func (m *Role) GetManagers() []string {
+	if m != nil {
+		return m.Managers
+	}
+	return nil
+}
+
+func (m *Role) GetManagerFilter() string {
+	if m != nil {
+		return m.ManagerFilter
+	}
+	return ""
+}
+
+func (m *Role) GetJoinApproval() bool {
+	if m != nil {
+		return m.JoinApproval
+	}
+	return false
+}
+
+func (m *Role) GetCapacity() int32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *Role) GetWaitlist() bool {
+	if m != nil {
+		return m.Waitlist
+	}
+	return false
+}
+
 func (m *Role) GetName() string {
 	if m != nil {
 		return m.Name
@@ -396,6 +1169,149 @@ func (m *Role) GetMentionable() bool {
 	return false
 }
 
+func (m *Role) GetDiscordId() string {
+	if m != nil {
+		return m.DiscordId
+	}
+	return ""
+}
+
+// RoleManagers is who manages a role. Adding sets Filter if it isn't empty;
+// removing clears it if it matches.
+type RoleManagers struct {
+	ShortName string   `protobuf:"bytes,1,opt,name=ShortName" json:"ShortName,omitempty"`
+	Users     []string `protobuf:"bytes,2,rep,name=Users" json:"Users,omitempty"`
+	Filter    string   `protobuf:"bytes,3,opt,name=Filter" json:"Filter,omitempty"`
+}
+
+func (m *RoleManagers) Reset()                    { *m = RoleManagers{} }
+func (m *RoleManagers) String() string            { return proto.CompactTextString(m) }
+func (*RoleManagers) ProtoMessage()               {}
+func (*RoleManagers) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *RoleManagers) GetShortName() string {
+	if m != nil {
+		return m.ShortName
+	}
+	return ""
+}
+
+func (m *RoleManagers) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *RoleManagers) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+// JoinRequest is a user waiting to be let into a SIG. ChannelId is where
+// they're told the outcome and Time is when they asked.
+type JoinRequest struct {
+	Role      string `protobuf:"bytes,1,opt,name=Role" json:"Role,omitempty"`
+	UserId    string `protobuf:"bytes,2,opt,name=UserId" json:"UserId,omitempty"`
+	ChannelId string `protobuf:"bytes,3,opt,name=ChannelId" json:"ChannelId,omitempty"`
+	Time      int64  `protobuf:"varint,4,opt,name=Time" json:"Time,omitempty"`
+}
+
+func (m *JoinRequest) Reset()                    { *m = JoinRequest{} }
+func (m *JoinRequest) String() string            { return proto.CompactTextString(m) }
+func (*JoinRequest) ProtoMessage()               {}
+func (*JoinRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *JoinRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *JoinRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *JoinRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *JoinRequest) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+// JoinResult is what joining a SIG did. Position is where on the waitlist
+// you are, counting from 1.
+type JoinResult struct {
+	State    JoinState `protobuf:"varint,1,opt,name=State,enum=chremoas.roles.JoinState" json:"State,omitempty"`
+	Position int32     `protobuf:"varint,2,opt,name=Position" json:"Position,omitempty"`
+}
+
+func (m *JoinResult) Reset()                    { *m = JoinResult{} }
+func (m *JoinResult) String() string            { return proto.CompactTextString(m) }
+func (*JoinResult) ProtoMessage()               {}
+func (*JoinResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *JoinResult) GetState() JoinState {
+	if m != nil {
+		return m.State
+	}
+	return JoinState_JOINED
+}
+
+func (m *JoinResult) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+// JoinRequestQuery with an empty Role is every request the caller can decide.
+type JoinRequestQuery struct {
+	Role string `protobuf:"bytes,1,opt,name=Role" json:"Role,omitempty"`
+}
+
+func (m *JoinRequestQuery) Reset()                    { *m = JoinRequestQuery{} }
+func (m *JoinRequestQuery) String() string            { return proto.CompactTextString(m) }
+func (*JoinRequestQuery) ProtoMessage()               {}
+func (*JoinRequestQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *JoinRequestQuery) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+// JoinRequestList is oldest first.
+type JoinRequestList struct {
+	Requests []*JoinRequest `protobuf:"bytes,1,rep,name=Requests" json:"Requests,omitempty"`
+}
+
+func (m *JoinRequestList) Reset()                    { *m = JoinRequestList{} }
+func (m *JoinRequestList) String() string            { return proto.CompactTextString(m) }
+func (*JoinRequestList) ProtoMessage()               {}
+func (*JoinRequestList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *JoinRequestList) GetRequests() []*JoinRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
 type UpdateInfo struct {
 	Name  string `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Key   string `protobuf:"bytes,2,opt,name=Key" json:"Key,omitempty"`
@@ -405,7 +1321,7 @@ type UpdateInfo struct {
 func (m *UpdateInfo) Reset()                    { *m = UpdateInfo{} }
 func (m *UpdateInfo) String() string            { return proto.CompactTextString(m) }
 func (*UpdateInfo) ProtoMessage()               {}
-func (*UpdateInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*UpdateInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *UpdateInfo) GetName() string {
 	if m != nil {
@@ -428,6 +1344,32 @@ func (m *UpdateInfo) GetValue() string {
 	return ""
 }
 
+// RoleUpdate sets the fields of Role named in Fields, which are Role field
+// names from GetRoleKeys, all at once. Role.ShortName picks the role.
+type RoleUpdate struct {
+	Role   *Role    `protobuf:"bytes,1,opt,name=Role" json:"Role,omitempty"`
+	Fields []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
+}
+
+func (m *RoleUpdate) Reset()                    { *m = RoleUpdate{} }
+func (m *RoleUpdate) String() string            { return proto.CompactTextString(m) }
+func (*RoleUpdate) ProtoMessage()               {}
+func (*RoleUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *RoleUpdate) GetRole() *Role {
+	if m != nil {
+		return m.Role
+	}
+	return nil
+}
+
+func (m *RoleUpdate) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type GetRolesResponse struct {
 	Roles []*Role `protobuf:"bytes,1,rep,name=Roles" json:"Roles,omitempty"`
 }
@@ -435,7 +1377,7 @@ type GetRolesResponse struct {
 func (m *GetRolesResponse) Reset()                    { *m = GetRolesResponse{} }
 func (m *GetRolesResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetRolesResponse) ProtoMessage()               {}
-func (*GetRolesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*GetRolesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
 
 func (m *GetRolesResponse) GetRoles() []*Role {
 	if m != nil {
@@ -451,7 +1393,7 @@ type FilterList struct {
 func (m *FilterList) Reset()                    { *m = FilterList{} }
 func (m *FilterList) String() string            { return proto.CompactTextString(m) }
 func (*FilterList) ProtoMessage()               {}
-func (*FilterList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*FilterList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
 func (m *FilterList) GetFilterList() []*Filter {
 	if m != nil {
@@ -468,7 +1410,7 @@ type Filter struct {
 func (m *Filter) Reset()                    { *m = Filter{} }
 func (m *Filter) String() string            { return proto.CompactTextString(m) }
 func (*Filter) ProtoMessage()               {}
-func (*Filter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*Filter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
 func (m *Filter) GetName() string {
 	if m != nil {
@@ -487,12 +1429,15 @@ func (m *Filter) GetDescription() string {
 type Members struct {
 	Name   []string `protobuf:"bytes,1,rep,name=Name" json:"Name,omitempty"`
 	Filter string   `protobuf:"bytes,2,opt,name=Filter" json:"Filter,omitempty"`
+	// Optional unix time each member in Name should be removed at, in the
+	// same order as Name. 0 means never. Leave it empty for no expiry at all.
+	ExpiresAt []int64 `protobuf:"varint,3,rep,packed,name=ExpiresAt" json:"ExpiresAt,omitempty"`
 }
 
 func (m *Members) Reset()                    { *m = Members{} }
 func (m *Members) String() string            { return proto.CompactTextString(m) }
 func (*Members) ProtoMessage()               {}
-func (*Members) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*Members) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
 
 func (m *Members) GetName() []string {
 	if m != nil {
@@ -508,14 +1453,23 @@ func (m *Members) GetFilter() string {
 	return ""
 }
 
+func (m *Members) GetExpiresAt() []int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
 type MemberList struct {
 	Members []string `protobuf:"bytes,1,rep,name=Members" json:"Members,omitempty"`
+	// When each member in Members expires, 0 for never.
+	ExpiresAt []int64 `protobuf:"varint,2,rep,packed,name=ExpiresAt" json:"ExpiresAt,omitempty"`
 }
 
 func (m *MemberList) Reset()                    { *m = MemberList{} }
 func (m *MemberList) String() string            { return proto.CompactTextString(m) }
 func (*MemberList) ProtoMessage()               {}
-func (*MemberList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*MemberList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
 func (m *MemberList) GetMembers() []string {
 	if m != nil {
@@ -524,6 +1478,13 @@ func (m *MemberList) GetMembers() []string {
 	return nil
 }
 
+func (m *MemberList) GetExpiresAt() []int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*NilMessage)(nil), "chremoas.roles.NilMessage")
 	proto.RegisterType((*RoleMembershipRequest)(nil), "chremoas.roles.RoleMembershipRequest")
@@ -534,77 +1495,180 @@ func init() {
 	proto.RegisterType((*GetDiscordUserListResponse)(nil), "chremoas.roles.GetDiscordUserListResponse")
 	proto.RegisterType((*GetDiscordUserResponse)(nil), "chremoas.roles.GetDiscordUserResponse")
 	proto.RegisterType((*SyncRequest)(nil), "chremoas.roles.SyncRequest")
+	proto.RegisterType((*SyncJob)(nil), "chremoas.roles.SyncJob")
+	proto.RegisterType((*SyncLock)(nil), "chremoas.roles.SyncLock")
+	proto.RegisterType((*SyncJobRequest)(nil), "chremoas.roles.SyncJobRequest")
+	proto.RegisterType((*SyncJobList)(nil), "chremoas.roles.SyncJobList")
+	proto.RegisterType((*SyncPlan)(nil), "chremoas.roles.SyncPlan")
+	proto.RegisterType((*RoleLink)(nil), "chremoas.roles.RoleLink")
+	proto.RegisterType((*RoleEdit)(nil), "chremoas.roles.RoleEdit")
+	proto.RegisterType((*FieldChange)(nil), "chremoas.roles.FieldChange")
+	proto.RegisterType((*MemberChange)(nil), "chremoas.roles.MemberChange")
+	proto.RegisterType((*ImportRolesRequest)(nil), "chremoas.roles.ImportRolesRequest")
+	proto.RegisterType((*ImportRolesResponse)(nil), "chremoas.roles.ImportRolesResponse")
+	proto.RegisterType((*AuditQuery)(nil), "chremoas.roles.AuditQuery")
+	proto.RegisterType((*AuditEntry)(nil), "chremoas.roles.AuditEntry")
+	proto.RegisterType((*AuditLog)(nil), "chremoas.roles.AuditLog")
 	proto.RegisterType((*StringList)(nil), "chremoas.roles.StringList")
 	proto.RegisterType((*Role)(nil), "chremoas.roles.Role")
+	proto.RegisterType((*RoleManagers)(nil), "chremoas.roles.RoleManagers")
+	proto.RegisterType((*JoinRequest)(nil), "chremoas.roles.JoinRequest")
+	proto.RegisterType((*JoinResult)(nil), "chremoas.roles.JoinResult")
+	proto.RegisterType((*JoinRequestQuery)(nil), "chremoas.roles.JoinRequestQuery")
+	proto.RegisterType((*JoinRequestList)(nil), "chremoas.roles.JoinRequestList")
 	proto.RegisterType((*UpdateInfo)(nil), "chremoas.roles.UpdateInfo")
+	proto.RegisterType((*RoleUpdate)(nil), "chremoas.roles.RoleUpdate")
 	proto.RegisterType((*GetRolesResponse)(nil), "chremoas.roles.GetRolesResponse")
 	proto.RegisterType((*FilterList)(nil), "chremoas.roles.FilterList")
 	proto.RegisterType((*Filter)(nil), "chremoas.roles.Filter")
 	proto.RegisterType((*Members)(nil), "chremoas.roles.Members")
 	proto.RegisterType((*MemberList)(nil), "chremoas.roles.MemberList")
+	proto.RegisterEnum("chremoas.roles.SyncMode", SyncMode_name, SyncMode_value)
+	proto.RegisterEnum("chremoas.roles.SyncJobState", SyncJobState_name, SyncJobState_value)
+	proto.RegisterEnum("chremoas.roles.JoinState", JoinState_name, JoinState_value)
 }
 
 func init() { proto.RegisterFile("roles.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 934 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x5f, 0x6f, 0x1b, 0x37,
-	0x0c, 0xb7, 0x9d, 0x38, 0xb1, 0xe9, 0x24, 0x68, 0x85, 0xc4, 0xbd, 0xdd, 0x86, 0xc1, 0x10, 0xda,
-	0x20, 0xe8, 0x00, 0x0f, 0xc8, 0xb0, 0xed, 0x65, 0x2b, 0xe6, 0x38, 0xcd, 0x9f, 0x76, 0x09, 0x8a,
-	0x73, 0xdb, 0x87, 0x3d, 0x0c, 0xb8, 0xf8, 0x18, 0x5b, 0xe8, 0xf9, 0xe4, 0x9d, 0xae, 0x01, 0xfc,
-	0x79, 0xf7, 0xb2, 0xcf, 0xb0, 0xa7, 0x81, 0x92, 0xee, 0x8f, 0xed, 0x8b, 0xdd, 0x25, 0x6f, 0x22,
-	0x45, 0xfe, 0x44, 0xfe, 0x48, 0x4a, 0x82, 0x56, 0x2c, 0x43, 0x54, 0xdd, 0x69, 0x2c, 0x13, 0xc9,
-	0xf6, 0x86, 0xe3, 0x18, 0x27, 0xd2, 0x57, 0x5d, 0xad, 0xe5, 0x3b, 0x00, 0xd7, 0x22, 0xbc, 0x42,
-	0xa5, 0xfc, 0x11, 0xf2, 0xef, 0xe0, 0xc0, 0x93, 0x21, 0x5e, 0xe1, 0xe4, 0x06, 0x63, 0x35, 0x16,
-	0x53, 0x0f, 0xff, 0xfa, 0x8c, 0x2a, 0x61, 0x0c, 0x36, 0xaf, 0xfd, 0x09, 0x3a, 0xd5, 0x4e, 0xf5,
-	0xa8, 0xe9, 0xe9, 0x35, 0x3f, 0x86, 0xf6, 0xa2, 0xb1, 0x9a, 0xca, 0x48, 0x21, 0x73, 0x60, 0xdb,
-	0x6a, 0x9d, 0x6a, 0x67, 0xe3, 0xa8, 0xe9, 0xa5, 0x22, 0xef, 0xc2, 0xfe, 0xef, 0x42, 0x25, 0x1f,
-	0x14, 0xc6, 0xe4, 0xab, 0x52, 0xfc, 0x36, 0x6c, 0x91, 0xee, 0x32, 0xb0, 0x27, 0x58, 0x89, 0xf7,
-	0xe1, 0x60, 0xc1, 0xde, 0x1e, 0xf1, 0x12, 0xea, 0x5a, 0xa1, 0x0f, 0x68, 0x1d, 0xef, 0x77, 0xe7,
-	0xf3, 0xea, 0xd2, 0xa6, 0x67, 0x4c, 0xf8, 0xf7, 0x70, 0x70, 0x8e, 0xc9, 0xa9, 0x50, 0x43, 0x19,
-	0x07, 0x1a, 0x6a, 0xcd, 0xa9, 0x7f, 0x80, 0x3b, 0xef, 0x40, 0x31, 0x64, 0x47, 0xff, 0x02, 0x75,
-	0xd2, 0xa5, 0x47, 0x1f, 0x2e, 0x1e, 0xbd, 0x78, 0x96, 0x71, 0xf3, 0x8c, 0x13, 0xff, 0xb7, 0x0a,
-	0xed, 0x72, 0x0b, 0xb6, 0x07, 0xb5, 0x2c, 0x94, 0xda, 0x65, 0xc0, 0x5c, 0x68, 0xd0, 0x7e, 0x44,
-	0xc4, 0xd7, 0xb4, 0x36, 0x93, 0xd9, 0x73, 0xd8, 0x25, 0x88, 0x58, 0x4c, 0x44, 0xe4, 0x27, 0x32,
-	0x76, 0x36, 0xb4, 0xc1, 0xbc, 0x92, 0x12, 0xec, 0xdd, 0xf9, 0x89, 0x1f, 0x3b, 0x9b, 0x26, 0x41,
-	0x23, 0xb1, 0x27, 0xb0, 0x71, 0x22, 0x13, 0xa7, 0xde, 0xa9, 0x1e, 0x35, 0x3c, 0x5a, 0xb2, 0x6f,
-	0x01, 0xae, 0x6e, 0xfd, 0xd7, 0x91, 0x7f, 0x13, 0x62, 0xe0, 0x6c, 0xe9, 0x8d, 0x82, 0x86, 0x62,
-	0xf9, 0x88, 0xb1, 0xb8, 0x15, 0x18, 0x38, 0xdb, 0x7a, 0x37, 0x93, 0xd9, 0x3e, 0xd4, 0x5f, 0x4f,
-	0x7c, 0x11, 0x3a, 0x0d, 0x7d, 0x88, 0x11, 0x74, 0xcb, 0x88, 0xe1, 0x27, 0xa7, 0x69, 0x5b, 0x46,
-	0x0c, 0x3f, 0x71, 0x84, 0xd6, 0x60, 0x16, 0x0d, 0x53, 0xfe, 0xbf, 0x81, 0x66, 0x7f, 0xec, 0x47,
-	0x11, 0x86, 0x59, 0xde, 0xb9, 0xa2, 0x50, 0x9d, 0x5a, 0xb1, 0x3a, 0xac, 0x03, 0xad, 0x01, 0x46,
-	0x81, 0xed, 0x59, 0x9d, 0x78, 0xc3, 0x2b, 0xaa, 0x38, 0x07, 0x18, 0x24, 0xb1, 0x88, 0x46, 0x54,
-	0x37, 0x0a, 0xef, 0xa3, 0x1f, 0x7e, 0x46, 0xdb, 0x8b, 0x46, 0xe0, 0xff, 0xd4, 0x60, 0x93, 0xda,
-	0x83, 0xe2, 0x7c, 0x3f, 0x9b, 0x66, 0xad, 0x4d, 0x6b, 0x0a, 0x6c, 0x30, 0x96, 0x71, 0x72, 0x9d,
-	0x53, 0x9f, 0x2b, 0xa8, 0xbd, 0xcf, 0x44, 0x98, 0x60, 0xdc, 0xb3, 0xac, 0xa7, 0x62, 0xbe, 0x73,
-	0x62, 0x09, 0x4f, 0x45, 0x62, 0x7c, 0x20, 0x46, 0x29, 0xe3, 0x03, 0x31, 0x22, 0x46, 0xdf, 0x48,
-	0xa1, 0xe9, 0xb5, 0x7c, 0x67, 0x32, 0xc5, 0x44, 0x3c, 0x59, 0xa6, 0xf5, 0x3a, 0x1b, 0xc1, 0xfd,
-	0x7c, 0x04, 0x29, 0xb5, 0xbe, 0x0c, 0x65, 0xec, 0x1c, 0x74, 0xaa, 0x47, 0x75, 0xcf, 0x08, 0xa4,
-	0xbd, 0x90, 0x42, 0x25, 0x4e, 0x5b, 0xbb, 0x1b, 0x81, 0xce, 0x7b, 0x27, 0x95, 0x48, 0x84, 0x8c,
-	0x9c, 0x67, 0xda, 0x3c, 0x93, 0x89, 0xd2, 0x77, 0x18, 0x4f, 0x84, 0x52, 0x42, 0x46, 0xca, 0x71,
-	0xf4, 0x76, 0x51, 0xa5, 0x47, 0xda, 0x8f, 0xfc, 0x11, 0x06, 0xce, 0x57, 0x1a, 0x35, 0x15, 0xc9,
-	0xf7, 0x0a, 0x23, 0x82, 0xd1, 0xa9, 0xb8, 0xa6, 0x1c, 0x05, 0x15, 0xbf, 0x00, 0xf8, 0x30, 0x0d,
-	0xfc, 0x04, 0x2f, 0xa3, 0x5b, 0x59, 0x76, 0x95, 0x10, 0x3b, 0x6f, 0x71, 0x66, 0x99, 0xa6, 0x65,
-	0x5e, 0x34, 0xc3, 0xb0, 0x2d, 0xda, 0x2b, 0x78, 0x72, 0x8e, 0xc9, 0xc3, 0x6f, 0x82, 0x53, 0x00,
-	0x53, 0x10, 0xdd, 0x18, 0x3f, 0x15, 0x25, 0xeb, 0xde, 0x5e, 0x74, 0x37, 0x16, 0x5e, 0xc1, 0x92,
-	0xbf, 0x82, 0x2d, 0x23, 0x95, 0xe6, 0xd2, 0x81, 0xd6, 0x29, 0xd2, 0x10, 0x4e, 0x35, 0xd5, 0x26,
-	0xa7, 0xa2, 0x8a, 0xff, 0x98, 0x5d, 0x8f, 0x05, 0x80, 0x8d, 0x0c, 0xa0, 0x9d, 0xc2, 0xa7, 0x7d,
-	0x6f, 0x24, 0x7e, 0x08, 0x60, 0xdc, 0x74, 0xf0, 0xf7, 0xde, 0xb1, 0xc7, 0x7f, 0x83, 0x65, 0x84,
-	0xfd, 0x0a, 0xdb, 0xbd, 0x20, 0xd0, 0x5d, 0x5e, 0x4a, 0x8b, 0xeb, 0x2e, 0x6a, 0x0b, 0x6f, 0x41,
-	0x85, 0x9d, 0xa5, 0x75, 0xd3, 0x08, 0x4b, 0xb6, 0x79, 0x4d, 0xd7, 0xe0, 0xfc, 0x06, 0xe0, 0xe1,
-	0x44, 0xde, 0xe1, 0x83, 0x23, 0x79, 0x03, 0x8d, 0xb4, 0xee, 0x6c, 0x85, 0xa5, 0xdb, 0x29, 0xb9,
-	0x8b, 0xe7, 0xba, 0x85, 0x57, 0xd8, 0xcf, 0xb0, 0x6d, 0xb5, 0xf7, 0x84, 0x52, 0xaa, 0xe5, 0x15,
-	0x76, 0x0e, 0x2d, 0xeb, 0xf8, 0x16, 0x67, 0xab, 0xe3, 0x58, 0xda, 0xcb, 0xaf, 0x23, 0x5e, 0x61,
-	0x17, 0xb0, 0x63, 0x81, 0xe8, 0xb2, 0x79, 0x0c, 0x52, 0x00, 0x4f, 0x2d, 0x52, 0xfe, 0x0a, 0xb3,
-	0x17, 0x65, 0xf1, 0x2f, 0x3d, 0xe9, 0xee, 0xe1, 0x3a, 0xb3, 0x8c, 0xb1, 0x3f, 0x61, 0x77, 0xee,
-	0x11, 0x66, 0xcf, 0x17, 0x5d, 0xcb, 0xde, 0x74, 0xf7, 0xc5, 0x1a, 0xab, 0x0c, 0xff, 0x0c, 0xe0,
-	0x1c, 0x13, 0xd3, 0xe5, 0xff, 0x93, 0x8d, 0xc2, 0x54, 0x56, 0x58, 0x0f, 0x9a, 0xbd, 0x20, 0xb0,
-	0xa3, 0x79, 0xcf, 0x20, 0xaf, 0x69, 0xb4, 0x53, 0xd8, 0x31, 0xad, 0xfa, 0x28, 0x94, 0x13, 0x9d,
-	0x50, 0x3a, 0xe3, 0x5f, 0x8c, 0x91, 0x4f, 0x37, 0xaf, 0xb0, 0x3e, 0x40, 0x2f, 0x08, 0x52, 0x8c,
-	0x67, 0xe5, 0xb6, 0x6a, 0xed, 0x04, 0xef, 0x9a, 0x74, 0x1e, 0x89, 0x73, 0x0d, 0x4f, 0xe9, 0x0d,
-	0x7a, 0x2f, 0xfb, 0x63, 0x3f, 0x19, 0x60, 0x7c, 0x27, 0x86, 0xc8, 0xbe, 0x5e, 0x6a, 0xcd, 0xfc,
-	0x69, 0x5f, 0x83, 0xe7, 0xc3, 0xde, 0xfc, 0x1f, 0x68, 0xb9, 0x69, 0x4b, 0x7f, 0x6c, 0xee, 0x17,
-	0x7e, 0xb6, 0x74, 0xd3, 0xb2, 0xe5, 0x3f, 0xdc, 0xca, 0xe6, 0x7a, 0xb9, 0x1a, 0xbb, 0xf8, 0x07,
-	0xe4, 0x95, 0x9b, 0x2d, 0xfd, 0x9f, 0xfe, 0xe1, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1c, 0xe4,
-	0xf0, 0x67, 0x5e, 0x0b, 0x00, 0x00,
+	// 2214 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xdb, 0x72, 0x1b, 0xc7,
+	0xd1, 0xc6, 0x91, 0x00, 0x1a, 0x14, 0x05, 0xcd, 0x4f, 0x51, 0x6b, 0x48, 0xe5, 0x9f, 0x35, 0xb1,
+	0x55, 0x2a, 0xd9, 0x25, 0xa7, 0xe8, 0x44, 0xae, 0x4a, 0x25, 0x2e, 0x83, 0x04, 0x40, 0x81, 0x02,
+	0x29, 0x6a, 0x41, 0xca, 0xb1, 0x2f, 0x92, 0x5a, 0x62, 0x47, 0xe4, 0x16, 0x81, 0x1d, 0x64, 0x76,
+	0xc9, 0x32, 0xde, 0x23, 0x8f, 0x90, 0xf7, 0xc8, 0x9b, 0xe4, 0x1d, 0x72, 0xeb, 0xab, 0x54, 0xf7,
+	0xcc, 0x9e, 0x80, 0x05, 0x28, 0x5b, 0xbe, 0xdb, 0xee, 0xe9, 0xf9, 0xa6, 0x4f, 0xd3, 0xdd, 0xbb,
+	0x0b, 0x4d, 0x25, 0x27, 0x22, 0x78, 0x31, 0x53, 0x32, 0x94, 0x6c, 0x6b, 0x7c, 0xa5, 0xc4, 0x54,
+	0x3a, 0xc1, 0x0b, 0xe2, 0xf2, 0x4d, 0x80, 0x13, 0x6f, 0x72, 0x2c, 0x82, 0xc0, 0xb9, 0x14, 0xfc,
+	0x0b, 0x78, 0x68, 0xcb, 0x89, 0x38, 0x16, 0xd3, 0x0b, 0xa1, 0x82, 0x2b, 0x6f, 0x66, 0x8b, 0x7f,
+	0xdc, 0x88, 0x20, 0x64, 0x0c, 0x2a, 0x27, 0xce, 0x54, 0x58, 0xc5, 0xdd, 0xe2, 0xb3, 0x86, 0x4d,
+	0xcf, 0x7c, 0x0f, 0x76, 0x16, 0x85, 0x83, 0x99, 0xf4, 0x03, 0xc1, 0x2c, 0xa8, 0x19, 0xae, 0x55,
+	0xdc, 0x2d, 0x3f, 0x6b, 0xd8, 0x11, 0xc9, 0x5f, 0xc0, 0xf6, 0xd0, 0x0b, 0xc2, 0xf3, 0x40, 0x28,
+	0xdc, 0x1b, 0x44, 0xf8, 0x3b, 0xb0, 0x81, 0xbc, 0x81, 0x6b, 0x4e, 0x30, 0x14, 0x3f, 0x80, 0x87,
+	0x0b, 0xf2, 0xe6, 0x88, 0xe7, 0x50, 0x25, 0x06, 0x1d, 0xd0, 0xdc, 0xdb, 0x7e, 0x91, 0xb5, 0xeb,
+	0x05, 0x2e, 0xda, 0x5a, 0x84, 0x7f, 0x05, 0x0f, 0x0f, 0x45, 0xd8, 0xf5, 0x82, 0xb1, 0x54, 0x2e,
+	0x41, 0xdd, 0x71, 0xea, 0x8f, 0xd0, 0xce, 0x6e, 0x40, 0x1d, 0xe2, 0xa3, 0xff, 0x0c, 0x55, 0xe4,
+	0x45, 0x47, 0x3f, 0x5d, 0x3c, 0x7a, 0xf1, 0x2c, 0xbd, 0xcd, 0xd6, 0x9b, 0xf8, 0xcf, 0x45, 0xd8,
+	0xc9, 0x97, 0x60, 0x5b, 0x50, 0x8a, 0x55, 0x29, 0x0d, 0x5c, 0xd6, 0x86, 0x3a, 0xae, 0xfb, 0xe8,
+	0xf8, 0x12, 0x71, 0x63, 0x9a, 0x7d, 0x06, 0xf7, 0x10, 0x42, 0x79, 0x53, 0xcf, 0x77, 0x42, 0xa9,
+	0xac, 0x32, 0x09, 0x64, 0x99, 0x68, 0x60, 0xe7, 0xd6, 0x09, 0x1d, 0x65, 0x55, 0xb4, 0x81, 0x9a,
+	0x62, 0x2d, 0x28, 0xef, 0xcb, 0xd0, 0xaa, 0xee, 0x16, 0x9f, 0xd5, 0x6d, 0x7c, 0x64, 0x9f, 0x02,
+	0x1c, 0xbf, 0x77, 0x7a, 0xbe, 0x73, 0x31, 0x11, 0xae, 0xb5, 0x41, 0x0b, 0x29, 0x0e, 0xea, 0xf2,
+	0x4e, 0x28, 0xef, 0xbd, 0x27, 0x5c, 0xab, 0x46, 0xab, 0x31, 0xcd, 0xb6, 0xa1, 0xda, 0x9b, 0x3a,
+	0xde, 0xc4, 0xaa, 0xd3, 0x21, 0x9a, 0xa0, 0x94, 0xf1, 0xc6, 0xd7, 0x56, 0xc3, 0xa4, 0x8c, 0x37,
+	0xbe, 0xe6, 0xff, 0x2d, 0x42, 0x73, 0x34, 0xf7, 0xc7, 0x51, 0x00, 0x9e, 0x40, 0xe3, 0xe0, 0xca,
+	0xf1, 0x7d, 0x31, 0x89, 0x0d, 0x4f, 0x18, 0xa9, 0xf0, 0x94, 0xd2, 0xe1, 0x61, 0xbb, 0xd0, 0x1c,
+	0x09, 0xdf, 0x35, 0x49, 0x4b, 0x96, 0xd7, 0xed, 0x34, 0x0b, 0x13, 0x50, 0xcb, 0x06, 0x56, 0x45,
+	0x27, 0xa0, 0x21, 0x51, 0x57, 0x9d, 0x37, 0x55, 0xe2, 0x6b, 0x82, 0x7d, 0x09, 0x95, 0x63, 0xe9,
+	0x0a, 0xb2, 0x7b, 0x6b, 0xcf, 0x5a, 0x8c, 0x28, 0xaa, 0x8c, 0xeb, 0x36, 0x49, 0xb1, 0x2f, 0xe1,
+	0xc1, 0xa9, 0x12, 0x81, 0x50, 0xb7, 0xe2, 0xdc, 0x9f, 0x3a, 0xbe, 0x73, 0x19, 0x3b, 0x65, 0x79,
+	0x81, 0xff, 0xbb, 0x0c, 0x35, 0x04, 0x38, 0x92, 0x17, 0x4b, 0x11, 0xde, 0x83, 0xea, 0x28, 0x74,
+	0x42, 0x1d, 0xde, 0xad, 0xbd, 0x27, 0x79, 0x07, 0x1f, 0xc9, 0x0b, 0x92, 0xb1, 0xb5, 0x68, 0xd6,
+	0x67, 0xe5, 0xd5, 0x3e, 0xab, 0x64, 0x7c, 0xb6, 0x03, 0x1b, 0x6f, 0x6f, 0xc4, 0x8d, 0x70, 0x29,
+	0xe8, 0x65, 0xdb, 0x50, 0xe8, 0xa9, 0x51, 0xe8, 0xa8, 0xd0, 0x04, 0xbd, 0x6c, 0x47, 0x24, 0x46,
+	0xbc, 0xef, 0xf9, 0x5e, 0x70, 0x65, 0x8c, 0x2b, 0xdb, 0x31, 0x8d, 0xd9, 0x47, 0xd9, 0x7c, 0x26,
+	0xcf, 0x67, 0x2e, 0xea, 0x8f, 0x91, 0xaf, 0xda, 0x59, 0x26, 0x7b, 0x0a, 0x5b, 0xc4, 0x38, 0x55,
+	0x72, 0x2c, 0x82, 0x40, 0xb8, 0x94, 0x0b, 0x55, 0x7b, 0x81, 0xcb, 0x38, 0x6c, 0x12, 0x47, 0x6f,
+	0x73, 0x2d, 0x20, 0xa9, 0x0c, 0x0f, 0x63, 0x4e, 0x74, 0xdf, 0xf1, 0x30, 0x41, 0x9b, 0x24, 0x92,
+	0x66, 0xa1, 0x85, 0x3d, 0xa5, 0xa4, 0x0a, 0xac, 0x4d, 0x0a, 0xad, 0xa1, 0xd0, 0x0e, 0x93, 0x6e,
+	0x81, 0x75, 0x8f, 0xb6, 0xc5, 0x34, 0xda, 0xd1, 0x99, 0x28, 0xe1, 0xb8, 0x73, 0xe3, 0x9c, 0x2d,
+	0x8a, 0x62, 0x96, 0xc9, 0x6d, 0xa8, 0x63, 0x20, 0x86, 0x72, 0x7c, 0x8d, 0xa7, 0xbc, 0x92, 0x13,
+	0x57, 0xa8, 0xa8, 0x64, 0x68, 0x0a, 0xf3, 0xaa, 0x2f, 0xfc, 0xb1, 0x8e, 0x64, 0xd9, 0xd6, 0x04,
+	0x7a, 0xb7, 0xf7, 0xd3, 0xcc, 0x53, 0x22, 0xa0, 0x48, 0x95, 0xed, 0x88, 0xe4, 0xbb, 0xb0, 0x65,
+	0x82, 0x1b, 0xdd, 0x85, 0x85, 0xdc, 0xe0, 0x7f, 0xd2, 0x57, 0xe5, 0x48, 0x5e, 0x60, 0xf5, 0x61,
+	0x5f, 0x40, 0xe5, 0x48, 0x5e, 0x44, 0x45, 0xe7, 0xd1, 0x8a, 0x4c, 0xb1, 0x49, 0x88, 0xff, 0xb3,
+	0xa4, 0x55, 0x3e, 0x9d, 0x38, 0x3e, 0xba, 0xee, 0x40, 0x09, 0xcc, 0xa0, 0xb8, 0x60, 0x36, 0xec,
+	0x34, 0x0b, 0x25, 0xba, 0x62, 0x22, 0x22, 0x89, 0x92, 0x96, 0x48, 0xb1, 0xd8, 0x4b, 0x68, 0xf4,
+	0x5c, 0x2f, 0xd4, 0xeb, 0x65, 0x52, 0xc1, 0xca, 0x2b, 0xb9, 0x24, 0x94, 0x88, 0xb2, 0x97, 0x49,
+	0x27, 0xa8, 0xd0, 0xae, 0xa5, 0x14, 0xd7, 0xcb, 0x98, 0xc0, 0x97, 0x22, 0xee, 0x13, 0x98, 0xe4,
+	0xa3, 0x6b, 0x6f, 0x96, 0xbe, 0xaa, 0x09, 0x03, 0xb5, 0x19, 0x7a, 0xfe, 0xb5, 0x5e, 0xdd, 0x58,
+	0xad, 0x0d, 0x09, 0x25, 0xa2, 0xbc, 0x0f, 0xf5, 0x88, 0x4d, 0x27, 0x5c, 0x49, 0x15, 0xa6, 0xda,
+	0x5a, 0xc2, 0xc0, 0x55, 0x53, 0xa1, 0xe3, 0xea, 0x93, 0x30, 0xf8, 0xb9, 0xc6, 0x41, 0x33, 0xf3,
+	0x3a, 0x23, 0xfb, 0x23, 0xd4, 0xb4, 0x41, 0xda, 0x97, 0xcd, 0xbd, 0xc7, 0x8b, 0xda, 0xf5, 0x3d,
+	0x31, 0x71, 0x23, 0xa3, 0x8d, 0x2c, 0x3f, 0x84, 0x66, 0x8a, 0x4f, 0x29, 0x85, 0xa4, 0x81, 0xd6,
+	0x04, 0x9e, 0xd7, 0x57, 0x72, 0x6a, 0x94, 0xa2, 0x67, 0x4c, 0x9d, 0x33, 0x69, 0x6a, 0x41, 0xe9,
+	0x4c, 0xf2, 0x09, 0x6c, 0xa6, 0xdd, 0xba, 0xaa, 0xcf, 0xad, 0x6d, 0x30, 0x2d, 0x28, 0x77, 0x5c,
+	0x97, 0x62, 0xdd, 0xb0, 0xf1, 0x11, 0x51, 0x6c, 0x31, 0x95, 0xb7, 0xc2, 0xd4, 0x54, 0x43, 0xf1,
+	0x7d, 0x60, 0x83, 0xe9, 0x4c, 0xaa, 0x30, 0xd3, 0xd1, 0xb7, 0xa1, 0x8a, 0xbe, 0x88, 0xf2, 0x4d,
+	0x13, 0x88, 0xd1, 0x55, 0x73, 0xfb, 0xc6, 0xa7, 0xf3, 0xea, 0xb6, 0xa1, 0xb8, 0x03, 0xff, 0x97,
+	0xc1, 0x30, 0x1d, 0xf1, 0xf7, 0x50, 0xd7, 0x6c, 0xe1, 0xae, 0x6d, 0xf4, 0xb1, 0x14, 0xd5, 0xb3,
+	0x6b, 0x6f, 0x36, 0x13, 0xae, 0x49, 0xe3, 0x88, 0xe4, 0x21, 0x40, 0xe7, 0xc6, 0xf5, 0xc2, 0xb7,
+	0x37, 0x42, 0xcd, 0x51, 0xbd, 0xce, 0x18, 0xfb, 0xa6, 0x71, 0x2e, 0x11, 0xa8, 0xde, 0x99, 0xa3,
+	0x2e, 0x45, 0x18, 0x75, 0x1c, 0x4d, 0xc5, 0x4e, 0xd7, 0x97, 0x38, 0xed, 0xf4, 0x0a, 0x71, 0x4a,
+	0x67, 0x12, 0x11, 0x87, 0xde, 0xd4, 0xd3, 0x5d, 0xb5, 0x6a, 0x6b, 0x82, 0xff, 0xab, 0x68, 0x8e,
+	0xed, 0xf9, 0xa1, 0x9a, 0xa7, 0x2e, 0x79, 0x99, 0x1a, 0x00, 0x83, 0xca, 0x99, 0x37, 0x8d, 0xaa,
+	0x06, 0x3d, 0x27, 0xaa, 0x95, 0x17, 0x54, 0x3b, 0x16, 0xe1, 0x95, 0x8c, 0x0b, 0xbb, 0xa6, 0x52,
+	0x2a, 0x57, 0x33, 0x2a, 0xef, 0xc0, 0xc6, 0xbe, 0x78, 0x2f, 0x95, 0x6e, 0x6a, 0x0d, 0xdb, 0x50,
+	0x84, 0xfe, 0x3e, 0x14, 0xca, 0xaa, 0x19, 0x74, 0x24, 0xf8, 0x77, 0x50, 0x27, 0x2d, 0x87, 0xf2,
+	0x92, 0xfd, 0x01, 0x6a, 0xa8, 0xac, 0x17, 0x0f, 0x57, 0xed, 0x45, 0x9f, 0x27, 0x06, 0xd9, 0x91,
+	0x28, 0xe7, 0x00, 0xa3, 0x50, 0x79, 0xfe, 0x25, 0x55, 0xab, 0x6d, 0xa8, 0xbe, 0x73, 0x26, 0x37,
+	0x22, 0x8a, 0x3e, 0x11, 0xfc, 0x3f, 0x15, 0xa8, 0x60, 0xbc, 0xc8, 0xec, 0xf9, 0x2c, 0xbe, 0x34,
+	0xf8, 0x9c, 0xbd, 0x90, 0xa5, 0xc5, 0x0b, 0x69, 0x41, 0xad, 0xef, 0x4d, 0x42, 0xa1, 0x3a, 0xc6,
+	0x2d, 0x11, 0x99, 0xac, 0xec, 0x1b, 0xcf, 0x44, 0x24, 0xa6, 0xf0, 0xc8, 0xbb, 0x8c, 0xa6, 0x9c,
+	0x91, 0x77, 0x89, 0x09, 0x7f, 0x24, 0x3d, 0x1a, 0x69, 0xcc, 0x8c, 0x13, 0xd3, 0xa8, 0x13, 0x96,
+	0x4c, 0xd3, 0xc8, 0xe9, 0x19, 0xa7, 0xa2, 0xde, 0x4f, 0x33, 0x25, 0x82, 0xc0, 0x93, 0xbe, 0x19,
+	0x6f, 0x52, 0x1c, 0x3c, 0xfb, 0xd4, 0x51, 0xc2, 0x0f, 0x03, 0xab, 0xa1, 0xb3, 0xcd, 0x90, 0x78,
+	0xd2, 0x31, 0x0d, 0x00, 0x2a, 0xb0, 0x80, 0x96, 0x62, 0x1a, 0xbb, 0x8e, 0x79, 0xd6, 0x9a, 0x52,
+	0x37, 0x6b, 0xd8, 0x59, 0x26, 0x76, 0x45, 0xd4, 0xad, 0x33, 0x9b, 0x29, 0x79, 0xeb, 0x4c, 0xac,
+	0x4d, 0xd2, 0x2b, 0xc3, 0xc3, 0x53, 0x0e, 0x9c, 0x99, 0x33, 0xf6, 0xc2, 0x79, 0xd4, 0xdb, 0x22,
+	0x1a, 0xd7, 0xbe, 0x77, 0xbc, 0x70, 0xe2, 0x05, 0xa1, 0x69, 0x6b, 0x31, 0x1d, 0x17, 0xad, 0xed,
+	0x54, 0xd1, 0xda, 0x86, 0xea, 0x81, 0x9c, 0x48, 0x65, 0x3d, 0xd4, 0xf9, 0x4b, 0x04, 0x72, 0x5f,
+	0x49, 0x84, 0xd8, 0x21, 0x08, 0x4d, 0x20, 0xf6, 0xa9, 0x0c, 0xbc, 0x10, 0xbd, 0xf2, 0x48, 0x9f,
+	0x1b, 0xd1, 0xd8, 0x4c, 0x4e, 0x85, 0x9a, 0x7a, 0xe4, 0xa1, 0xc0, 0xb2, 0x74, 0xa7, 0x4e, 0xb1,
+	0xe8, 0xf5, 0xc0, 0x4c, 0x4d, 0x9f, 0x10, 0x6a, 0x44, 0xe2, 0xde, 0x63, 0xe1, 0x23, 0x0c, 0x85,
+	0xa8, 0x4d, 0xab, 0x69, 0x56, 0xb6, 0x30, 0x3f, 0x5e, 0x2c, 0xcc, 0x3f, 0xc2, 0x26, 0xbd, 0x92,
+	0x44, 0x9e, 0x5e, 0x5f, 0xe4, 0xb7, 0xa3, 0x41, 0x5e, 0x57, 0x0a, 0x4d, 0xe0, 0xc5, 0x31, 0x61,
+	0xd1, 0x89, 0x66, 0x28, 0x7e, 0x0d, 0x4d, 0xf4, 0x7d, 0xea, 0x8d, 0x08, 0x8f, 0x8a, 0x52, 0x18,
+	0x9f, 0x57, 0x0e, 0xac, 0xeb, 0x47, 0xb6, 0xa8, 0x06, 0x54, 0x92, 0x1a, 0xc0, 0x7f, 0x00, 0xd0,
+	0x87, 0x05, 0x37, 0x93, 0x90, 0x7d, 0x15, 0x8d, 0x89, 0x45, 0x1a, 0x13, 0x3f, 0x59, 0xbc, 0x8f,
+	0x28, 0x9a, 0x99, 0x11, 0xd3, 0xf1, 0x29, 0x65, 0xe3, 0xc3, 0x9f, 0x42, 0x2b, 0x65, 0x87, 0xae,
+	0x86, 0x39, 0xc6, 0xf0, 0x23, 0xb8, 0x9f, 0x92, 0xa3, 0x5b, 0xfd, 0x4d, 0x6a, 0x94, 0x2a, 0xe6,
+	0x37, 0xb6, 0xd4, 0x96, 0x64, 0xce, 0xe2, 0xaf, 0x00, 0xf4, 0x20, 0x37, 0xf0, 0xdf, 0xcb, 0xdc,
+	0x96, 0xd9, 0x82, 0xf2, 0x6b, 0x31, 0x37, 0x7e, 0xc3, 0xc7, 0xa4, 0x84, 0x98, 0x32, 0x48, 0x04,
+	0x3f, 0x01, 0x40, 0xed, 0x34, 0x1a, 0x7b, 0x96, 0xd2, 0x7b, 0x55, 0x6f, 0x88, 0x43, 0x43, 0xfd,
+	0x33, 0x0a, 0xb6, 0xa1, 0xf8, 0xb7, 0xd0, 0x3a, 0x14, 0xe1, 0xaf, 0x7f, 0xb7, 0xec, 0x02, 0xe8,
+	0xfc, 0x20, 0x07, 0xbd, 0x4c, 0x53, 0x66, 0xfb, 0xce, 0x72, 0xef, 0x47, 0x09, 0x3b, 0x25, 0xc9,
+	0xbf, 0x8d, 0x72, 0x2e, 0xd7, 0x37, 0x34, 0x9e, 0xe1, 0x6b, 0xdd, 0x2c, 0x0e, 0x68, 0xc3, 0x4e,
+	0xb3, 0xf8, 0x28, 0x1e, 0xb3, 0x52, 0x00, 0xe5, 0x18, 0x20, 0x49, 0xe9, 0x52, 0x3a, 0xa5, 0x31,
+	0x2f, 0xcd, 0x3c, 0xda, 0x09, 0xa9, 0xd3, 0x97, 0xed, 0x84, 0x81, 0xa6, 0x69, 0x50, 0x32, 0x6d,
+	0xe5, 0x3b, 0x7d, 0x16, 0xa5, 0xb4, 0x80, 0xf2, 0xfc, 0x6b, 0x3d, 0x89, 0xd2, 0x8b, 0x53, 0x1d,
+	0x2a, 0xfd, 0xf3, 0xe1, 0xb0, 0x55, 0x60, 0x9b, 0x50, 0xef, 0x74, 0xbb, 0x7f, 0x7f, 0x73, 0x32,
+	0xfc, 0xa1, 0x55, 0x64, 0xf7, 0xa1, 0x69, 0xf7, 0x8e, 0xdf, 0xbc, 0xeb, 0x69, 0x46, 0xe9, 0xf9,
+	0x3e, 0x6c, 0xa6, 0x5f, 0x7d, 0x18, 0xc0, 0xc6, 0xdb, 0xf3, 0xde, 0x79, 0xaf, 0xdb, 0x2a, 0xb0,
+	0x26, 0xd4, 0xec, 0xf3, 0x93, 0x93, 0xc1, 0xc9, 0x61, 0xab, 0xc8, 0xee, 0x41, 0x63, 0x74, 0x7e,
+	0x70, 0xd0, 0xeb, 0x75, 0x7b, 0xdd, 0x56, 0x09, 0xe5, 0xfa, 0x9d, 0xc1, 0xb0, 0xd7, 0x6d, 0x95,
+	0x9f, 0xbf, 0x84, 0x46, 0x7c, 0x2f, 0x70, 0xe1, 0xe8, 0xcd, 0xe0, 0x84, 0x00, 0xb6, 0x00, 0xbe,
+	0xef, 0x0c, 0xce, 0x86, 0x83, 0xd1, 0x59, 0xaf, 0xab, 0x31, 0xec, 0xde, 0xdb, 0xf3, 0x1e, 0x91,
+	0xa5, 0xbd, 0x9f, 0xa3, 0x57, 0x44, 0xf6, 0x17, 0xa8, 0x75, 0x5c, 0x17, 0x9f, 0x59, 0x6e, 0x0e,
+	0xb4, 0x97, 0x1a, 0x63, 0xea, 0x53, 0x4a, 0x81, 0xf5, 0xa3, 0xa4, 0x27, 0x84, 0x25, 0xd9, 0xe4,
+	0x42, 0xdc, 0x89, 0xd3, 0x4a, 0x70, 0x74, 0xda, 0x2e, 0xa3, 0x25, 0x97, 0xa2, 0x9d, 0xab, 0x2b,
+	0x2f, 0xb0, 0xef, 0x00, 0xf4, 0xc4, 0xf6, 0xab, 0x2d, 0x3a, 0x82, 0x7a, 0x74, 0x59, 0xd8, 0x1a,
+	0xc9, 0xf6, 0x6e, 0xce, 0x27, 0x91, 0xcc, 0x15, 0xe3, 0x05, 0xf6, 0x0d, 0xd4, 0x0c, 0x77, 0x85,
+	0x2a, 0xab, 0xcc, 0x38, 0x84, 0xa6, 0xd9, 0xf8, 0x5a, 0xcc, 0xd7, 0xeb, 0xb1, 0xb4, 0x96, 0x4c,
+	0x28, 0xbc, 0xc0, 0x5e, 0xc1, 0xa6, 0x01, 0xc2, 0xf9, 0xe3, 0x63, 0x90, 0x0e, 0xe1, 0xbe, 0x41,
+	0x8a, 0x3b, 0x4f, 0xbe, 0x4d, 0x4f, 0xf2, 0xb8, 0xd1, 0x1e, 0x5e, 0x60, 0x6f, 0xe0, 0xbe, 0xc9,
+	0xb8, 0xa4, 0x85, 0xad, 0xdb, 0x72, 0x27, 0xa0, 0x0d, 0x2c, 0x89, 0xf9, 0x6f, 0x84, 0xe9, 0xc2,
+	0x83, 0xc8, 0xda, 0xf8, 0xd3, 0x1f, 0xfb, 0x3c, 0x77, 0xd3, 0xe2, 0x77, 0xc4, 0xf6, 0xd3, 0xbb,
+	0xc4, 0xe2, 0xfc, 0xf8, 0x1b, 0xdc, 0xcb, 0x7c, 0xf9, 0x63, 0x9f, 0x2d, 0x6e, 0xcd, 0xfb, 0x90,
+	0xd8, 0xfe, 0xfc, 0x0e, 0xa9, 0x18, 0xbf, 0x0f, 0x70, 0x28, 0x42, 0x5d, 0x08, 0x7f, 0x61, 0xec,
+	0x53, 0x85, 0xbb, 0xc0, 0x3a, 0xd0, 0xe8, 0xb8, 0xae, 0x66, 0xb1, 0x15, 0xb5, 0xfe, 0x8e, 0x6b,
+	0xd5, 0x85, 0x4d, 0x1d, 0xa4, 0x8f, 0x42, 0xd9, 0x27, 0x83, 0xa2, 0xa2, 0xfc, 0xc1, 0x18, 0x49,
+	0x89, 0xe7, 0x05, 0x76, 0x00, 0xd0, 0x71, 0xdd, 0x08, 0xe3, 0x51, 0xbe, 0x6c, 0x70, 0x67, 0xbd,
+	0xba, 0xa7, 0xcd, 0xf9, 0x48, 0x9c, 0x2e, 0xd4, 0xa8, 0x80, 0x0f, 0x0e, 0xd9, 0xba, 0x31, 0xa3,
+	0xdd, 0xce, 0x5f, 0xc4, 0xc9, 0x89, 0x17, 0xd8, 0x00, 0x9a, 0x46, 0x10, 0xd9, 0xeb, 0x91, 0xd6,
+	0x2d, 0xf2, 0x02, 0x7b, 0x47, 0xd7, 0x3c, 0xc5, 0x0b, 0xd8, 0xee, 0x9a, 0x1d, 0x34, 0x5a, 0xb5,
+	0xff, 0x7f, 0x8d, 0x84, 0xf1, 0xfa, 0x1b, 0x60, 0x7a, 0xa2, 0x17, 0xa9, 0xb5, 0x5f, 0x68, 0x73,
+	0xc6, 0x73, 0x43, 0xb8, 0xdf, 0x15, 0xfe, 0xfc, 0x37, 0x42, 0xeb, 0x53, 0xc1, 0x8d, 0xdf, 0x1d,
+	0xf2, 0x2b, 0xdb, 0x87, 0x99, 0x69, 0xd2, 0x5c, 0xc9, 0x69, 0x0c, 0xf7, 0x11, 0x8a, 0xbd, 0x86,
+	0x07, 0x38, 0x25, 0x9c, 0xc9, 0x83, 0x2b, 0x27, 0x1c, 0x09, 0x75, 0xeb, 0x8d, 0xc5, 0x32, 0x5e,
+	0xea, 0x7b, 0x73, 0x7b, 0xd5, 0x67, 0x33, 0x4a, 0xfd, 0x3a, 0x7e, 0x2d, 0x43, 0xc6, 0x7a, 0x8c,
+	0xdc, 0xaf, 0xc3, 0xb8, 0x95, 0x1a, 0x01, 0xde, 0x41, 0x03, 0xca, 0x3e, 0x5d, 0x71, 0xda, 0x07,
+	0x68, 0xf3, 0x1a, 0xb6, 0x0c, 0xd0, 0x2b, 0x2f, 0x08, 0xa5, 0x9a, 0xaf, 0xad, 0x50, 0x8f, 0x57,
+	0x00, 0x19, 0xc7, 0xf7, 0x28, 0x80, 0xf1, 0x27, 0xcc, 0x75, 0x48, 0xb9, 0xc6, 0xe1, 0x2e, 0x5e,
+	0x60, 0x7f, 0x85, 0x66, 0xea, 0x1b, 0x0d, 0xe3, 0x8b, 0xa2, 0xcb, 0x1f, 0x81, 0xda, 0xbf, 0x5b,
+	0x2b, 0x13, 0xd7, 0x62, 0xad, 0x60, 0xfc, 0x01, 0x22, 0xff, 0x7b, 0x83, 0xbe, 0x4e, 0x56, 0xee,
+	0xda, 0x50, 0x5e, 0xf2, 0x02, 0x73, 0xc8, 0x69, 0xa9, 0x3f, 0x2b, 0xcb, 0x5d, 0x29, 0xf7, 0x3f,
+	0x50, 0xfb, 0x03, 0x7f, 0xe1, 0x50, 0x57, 0x62, 0xcb, 0x7f, 0x86, 0xd6, 0x7a, 0xf4, 0xf9, 0x7a,
+	0xec, 0xf4, 0x9f, 0x25, 0x5e, 0xb8, 0xd8, 0xa0, 0xbf, 0x74, 0x5f, 0xff, 0x6f, 0x00, 0x87, 0x78,
+	0x2a, 0x48, 0xb4, 0x1b, 0x00, 0x00,
 }
@@ -1,4 +1,4 @@
-DROP INDEX role_name_uindex ON roles;
-DROP TABLE roles;
 DROP INDEX member_uindex ON role_membership;
-CREATE TABLE role_membership;
+DROP TABLE role_membership;
+DROP INDEX name_uindex ON roles;
+DROP TABLE roles;
@@ -0,0 +1,21 @@
+DROP TABLE no_sync_members;
+DROP TABLE role_filters;
+DROP INDEX filter_member_uindex ON filter_membership;
+DROP TABLE filter_membership;
+DROP INDEX filter_name_uindex ON filters;
+DROP TABLE filters;
+
+CREATE TABLE role_membership (
+  id BIGINT(20) PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  role BIGINT REFERENCES roles (id),
+  member VARCHAR(256) NOT NULL
+);
+
+CREATE UNIQUE INDEX member_uindex ON role_membership (member);
+
+DROP INDEX short_name_uindex ON roles;
+ALTER TABLE roles DROP COLUMN sync;
+ALTER TABLE roles DROP COLUMN joinable;
+ALTER TABLE roles DROP COLUMN sig;
+ALTER TABLE roles DROP COLUMN type;
+ALTER TABLE roles DROP COLUMN short_name;
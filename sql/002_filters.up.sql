@@ -0,0 +1,44 @@
+ALTER TABLE roles ADD COLUMN short_name VARCHAR(70) NOT NULL AFTER id;
+ALTER TABLE roles ADD COLUMN type VARCHAR(32) NOT NULL DEFAULT 'discord';
+ALTER TABLE roles ADD COLUMN sig BOOL DEFAULT FALSE;
+ALTER TABLE roles ADD COLUMN joinable BOOL DEFAULT FALSE;
+ALTER TABLE roles ADD COLUMN sync BOOL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX short_name_uindex ON roles (short_name);
+
+-- Membership is computed from filters, it was never stored per role.
+DROP INDEX member_uindex ON role_membership;
+DROP TABLE role_membership;
+
+CREATE TABLE filters (
+  id BIGINT(20) PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  name VARCHAR(70) NOT NULL,
+  description VARCHAR(256) NOT NULL,
+
+  inserted TIMESTAMP NOT NULL,
+  updated TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX filter_name_uindex ON filters (name);
+
+CREATE TABLE filter_membership (
+  id BIGINT(20) PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  filter BIGINT NOT NULL REFERENCES filters (id),
+  member VARCHAR(256) NOT NULL
+);
+
+CREATE UNIQUE INDEX filter_member_uindex ON filter_membership (filter, member);
+
+-- slot is 'A' or 'B'. filter is a name rather than an id because it may be
+-- the 'wildcard' pseudo filter.
+CREATE TABLE role_filters (
+  role BIGINT NOT NULL REFERENCES roles (id),
+  slot CHAR(1) NOT NULL,
+  filter VARCHAR(70) NOT NULL,
+
+  PRIMARY KEY (role, slot)
+);
+
+CREATE TABLE no_sync_members (
+  member VARCHAR(256) PRIMARY KEY NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE roles ADD COLUMN expression VARCHAR(1024) NOT NULL DEFAULT '' AFTER sync;
@@ -0,0 +1,2 @@
+-- Comma separated ShortNames of the roles this one counts towards.
+ALTER TABLE roles ADD COLUMN parents VARCHAR(1024) NOT NULL DEFAULT '' AFTER expression;
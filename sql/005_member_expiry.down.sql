@@ -0,0 +1,2 @@
+DROP INDEX expires_at_index ON filter_membership;
+ALTER TABLE filter_membership DROP COLUMN expires_at;
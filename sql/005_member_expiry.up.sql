@@ -0,0 +1,4 @@
+-- When a filter membership should be removed, NULL for never.
+ALTER TABLE filter_membership ADD COLUMN expires_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE INDEX expires_at_index ON filter_membership (expires_at);
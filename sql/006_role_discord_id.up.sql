@@ -0,0 +1,2 @@
+-- Id of the Discord role each role is synced to.
+ALTER TABLE roles ADD COLUMN discord_id VARCHAR(32) NOT NULL DEFAULT '' AFTER mentionable;
@@ -0,0 +1,2 @@
+ALTER TABLE roles DROP COLUMN manager_filter;
+ALTER TABLE roles DROP COLUMN managers;
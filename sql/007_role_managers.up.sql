@@ -0,0 +1,4 @@
+-- Comma separated Discord user Ids allowed to manage the role, and a filter
+-- whose members are also allowed to.
+ALTER TABLE roles ADD COLUMN managers VARCHAR(1024) NOT NULL DEFAULT '' AFTER parents;
+ALTER TABLE roles ADD COLUMN manager_filter VARCHAR(70) NOT NULL DEFAULT '' AFTER managers;
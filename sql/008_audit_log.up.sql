@@ -0,0 +1,14 @@
+-- Append-only log of changes. before_value and after_value are JSON.
+CREATE TABLE audit_log (
+  id BIGINT(20) PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  time BIGINT NOT NULL,
+  actor VARCHAR(256) NOT NULL,
+  method VARCHAR(70) NOT NULL,
+  target VARCHAR(256) NOT NULL,
+  before_value TEXT NOT NULL,
+  after_value TEXT NOT NULL
+);
+
+CREATE INDEX audit_time_index ON audit_log (time);
+CREATE INDEX audit_actor_index ON audit_log (actor, time);
+CREATE INDEX audit_target_index ON audit_log (target, time);
@@ -0,0 +1,2 @@
+DROP TABLE join_requests;
+ALTER TABLE roles DROP COLUMN join_approval;
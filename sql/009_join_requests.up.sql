@@ -0,0 +1,12 @@
+-- SIGs whose join requests need approving by a manager.
+ALTER TABLE roles ADD COLUMN join_approval BOOL DEFAULT FALSE AFTER joinable;
+
+CREATE TABLE join_requests (
+  role BIGINT NOT NULL REFERENCES roles (id),
+  member VARCHAR(256) NOT NULL,
+  -- Where to tell the member how it went
+  channel_id VARCHAR(70) NOT NULL,
+  requested BIGINT NOT NULL,
+
+  PRIMARY KEY (role, member)
+);
@@ -0,0 +1,3 @@
+DROP TABLE role_waitlist;
+ALTER TABLE roles DROP COLUMN waitlist;
+ALTER TABLE roles DROP COLUMN capacity;
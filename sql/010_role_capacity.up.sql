@@ -0,0 +1,14 @@
+-- The most members a SIG can have, 0 for no limit, and whether joining a full
+-- one puts you on its waitlist rather than refusing.
+ALTER TABLE roles ADD COLUMN capacity INT NOT NULL DEFAULT 0 AFTER join_approval;
+ALTER TABLE roles ADD COLUMN waitlist BOOL DEFAULT FALSE AFTER capacity;
+
+-- Same shape as join_requests, first in line is the oldest.
+CREATE TABLE role_waitlist (
+  role BIGINT NOT NULL REFERENCES roles (id),
+  member VARCHAR(256) NOT NULL,
+  channel_id VARCHAR(70) NOT NULL,
+  requested BIGINT NOT NULL,
+
+  PRIMARY KEY (role, member)
+);
@@ -0,0 +1,41 @@
+package storage
+
+// AuditEntry is one change recorded in the audit log. Before and After are
+// whatever the handler recorded, usually JSON, and empty where there was
+// nothing before or nothing is left after.
+type AuditEntry struct {
+	Id     int64
+	Time   int64
+	Actor  string
+	Method string
+	Target string
+	Before string
+	After  string
+}
+
+// AuditQuery picks entries out of the audit log. Empty fields match
+// everything. From and To are unix times, both inclusive, and To of 0 means
+// now. Limit of 0 means no limit.
+type AuditQuery struct {
+	Actor  string
+	Target string
+	From   int64
+	To     int64
+	Limit  int
+}
+
+func (q AuditQuery) matches(e *AuditEntry) bool {
+	return (q.Actor == "" || q.Actor == e.Actor) &&
+		(q.Target == "" || q.Target == e.Target) &&
+		e.Time >= q.From &&
+		(q.To == 0 || e.Time <= q.To)
+}
+
+// AuditStore is an append-only log of changes.
+type AuditStore interface {
+	// AppendAudit adds entry to the log and sets its Id. Entries are never
+	// changed, but only the newest roles.auditRetention are kept.
+	AppendAudit(entry *AuditEntry) error
+	// GetAudit returns the entries matching query, newest first.
+	GetAudit(query AuditQuery) ([]AuditEntry, error)
+}
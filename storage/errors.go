@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrRoleExists     = errors.New("role already exists")
+	ErrRoleNotFound   = errors.New("role doesn't exist")
+	ErrFilterExists   = errors.New("filter already exists")
+	ErrFilterNotFound = errors.New("filter doesn't exist")
+	ErrFilterNotEmpty = errors.New("filter isn't empty")
+
+	ErrJoinRequestExists   = errors.New("join request already exists")
+	ErrJoinRequestNotFound = errors.New("join request doesn't exist")
+	ErrWaitlisted          = errors.New("already on the waitlist")
+	ErrNotWaitlisted       = errors.New("not on the waitlist")
+
+	// ErrConflict means something else changed the data between the checks
+	// and the write. Nothing was written and the call can be retried.
+	ErrConflict = errors.New("conflicting change made at the same time, try again")
+)
+
+// MissingFilterError is returned by CreateRole when one of the filters the
+// role depends on doesn't exist.
+type MissingFilterError struct {
+	Name string
+}
+
+func (e *MissingFilterError) Error() string {
+	return fmt.Sprintf("filter %s doesn't exist", e.Name)
+}
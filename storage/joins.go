@@ -0,0 +1,53 @@
+package storage
+
+import "sort"
+
+// JoinRequest is a user waiting to be let into a SIG. ChannelId is where to
+// tell them how it went.
+type JoinRequest struct {
+	Role      string
+	User      string
+	ChannelId string
+	Time      int64
+}
+
+// JoinRequestStore keeps the pending requests to join SIGs that need
+// approval.
+type JoinRequestStore interface {
+	// AddJoinRequest stores a new request or returns ErrJoinRequestExists.
+	// Removing the role drops its requests.
+	AddJoinRequest(request *JoinRequest) error
+	// GetJoinRequests returns the requests to join role, or every role if
+	// role is empty, oldest first.
+	GetJoinRequests(role string) ([]JoinRequest, error)
+	// RemoveJoinRequest deletes a request or returns ErrJoinRequestNotFound.
+	RemoveJoinRequest(role, user string) error
+}
+
+// WaitlistStore keeps the users waiting for a seat in a full SIG, as
+// JoinRequests.
+type WaitlistStore interface {
+	// AddToWaitlist puts entry at the back of its role's waitlist or returns
+	// ErrWaitlisted. Removing the role drops its waitlist.
+	AddToWaitlist(entry *JoinRequest) error
+	// GetWaitlist returns the waitlist of role, or of every role if role is
+	// empty, first in line first.
+	GetWaitlist(role string) ([]JoinRequest, error)
+	// RemoveFromWaitlist takes user off the waitlist of role or returns
+	// ErrNotWaitlisted.
+	RemoveFromWaitlist(role, user string) error
+}
+
+// sortJoinRequests puts requests oldest first, for the backends that can't
+// sort them as they're read.
+func sortJoinRequests(requests []JoinRequest) {
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Time != requests[j].Time {
+			return requests[i].Time < requests[j].Time
+		}
+		if requests[i].Role != requests[j].Role {
+			return requests[i].Role < requests[j].Role
+		}
+		return requests[i].User < requests[j].User
+	})
+}
@@ -0,0 +1,463 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/chremoas/services-common/sets"
+)
+
+// MemoryStore keeps everything in process memory. Nothing survives a restart,
+// so it's only really useful for development and for exercising the handler
+// without a live Redis.
+type MemoryStore struct {
+	mutex         sync.RWMutex
+	roles         map[string]map[string]string
+	filters       map[string]string
+	filterMembers map[string]*sets.StringSet
+	expiries      map[string]map[string]int64
+	noSync        *sets.StringSet
+	joinRequests  map[string]map[string]JoinRequest
+	waitlists     map[string]map[string]JoinRequest
+	audit         []AuditEntry
+	auditId       int64
+	// auditRetention is how many audit entries are kept, 0 for all of them.
+	auditRetention int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		roles:         make(map[string]map[string]string),
+		filters:       make(map[string]string),
+		filterMembers: make(map[string]*sets.StringSet),
+		expiries:      make(map[string]map[string]int64),
+		noSync:        sets.NewStringSet(),
+		joinRequests:  make(map[string]map[string]JoinRequest),
+		waitlists:     make(map[string]map[string]JoinRequest),
+	}
+}
+
+func (s *MemoryStore) GetRoles() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var roleList []string
+	for role := range s.roles {
+		roleList = append(roleList, role)
+	}
+
+	return roleList, nil
+}
+
+func (s *MemoryStore) RoleExists(name string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.roles[name]
+	return ok, nil
+}
+
+func (s *MemoryStore) GetRole(name string) (map[string]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	role := make(map[string]string)
+	for k, v := range s.roles[name] {
+		role[k] = v
+	}
+
+	return role, nil
+}
+
+func (s *MemoryStore) SetRole(name string, fields map[string]string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.roles[name]; !ok {
+		s.roles[name] = make(map[string]string)
+	}
+
+	for k, v := range fields {
+		s.roles[name][k] = v
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteRole(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.roles, name)
+	delete(s.joinRequests, name)
+	delete(s.waitlists, name)
+	return nil
+}
+
+// The checked mutations hold the write lock across the checks and the write,
+// which is all it takes to make them atomic here.
+
+func (s *MemoryStore) CreateRole(name string, fields map[string]string, filters []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.roles[name]; ok {
+		return ErrRoleExists
+	}
+
+	if err := s.checkFilters(filters); err != nil {
+		return err
+	}
+
+	s.roles[name] = make(map[string]string)
+	for k, v := range fields {
+		s.roles[name][k] = v
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) UpdateRole(name string, fields map[string]string, filters []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	role, ok := s.roles[name]
+	if !ok {
+		return ErrRoleNotFound
+	}
+
+	if err := s.checkFilters(filters); err != nil {
+		return err
+	}
+
+	for k, v := range fields {
+		role[k] = v
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) checkFilters(filters []string) error {
+	for f := range filters {
+		if _, ok := s.filters[filters[f]]; !ok {
+			return &MissingFilterError{Name: filters[f]}
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) RemoveRole(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.roles[name]; !ok {
+		return ErrRoleNotFound
+	}
+
+	delete(s.roles, name)
+	return nil
+}
+
+func (s *MemoryStore) GetFilters() (map[string]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	filterList := make(map[string]string)
+	for k, v := range s.filters {
+		filterList[k] = v
+	}
+
+	return filterList, nil
+}
+
+func (s *MemoryStore) FilterExists(name string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.filters[name]
+	return ok, nil
+}
+
+func (s *MemoryStore) SetFilter(name, description string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.filters[name] = description
+	return nil
+}
+
+func (s *MemoryStore) DeleteFilter(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.filters, name)
+	delete(s.expiries, name)
+	return nil
+}
+
+func (s *MemoryStore) CreateFilter(name, description string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.filters[name]; ok {
+		return ErrFilterExists
+	}
+
+	s.filters[name] = description
+	return nil
+}
+
+func (s *MemoryStore) RemoveFilter(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.filters[name]; !ok {
+		return ErrFilterNotFound
+	}
+
+	if members, ok := s.filterMembers[name]; ok && members.Len() > 0 {
+		return ErrFilterNotEmpty
+	}
+
+	delete(s.filters, name)
+	return nil
+}
+
+func (s *MemoryStore) GetFilterMembers(name string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if members, ok := s.filterMembers[name]; ok {
+		return members.ToSlice(), nil
+	}
+
+	return nil, nil
+}
+
+func (s *MemoryStore) AddFilterMembers(name string, members []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.filterMembers[name]; !ok {
+		s.filterMembers[name] = sets.NewStringSet()
+	}
+	s.filterMembers[name].FromSlice(members)
+
+	return nil
+}
+
+func (s *MemoryStore) RemoveFilterMembers(name string, members []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if set, ok := s.filterMembers[name]; ok {
+		for m := range members {
+			set.Remove(members[m])
+			delete(s.expiries[name], members[m])
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) SetMemberExpiries(name string, expiries map[string]int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.expiries[name]; !ok {
+		s.expiries[name] = make(map[string]int64)
+	}
+
+	for member, expiry := range expiries {
+		if expiry == 0 {
+			delete(s.expiries[name], member)
+		} else {
+			s.expiries[name][member] = expiry
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetMemberExpiries(name string) (map[string]int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	expiries := make(map[string]int64)
+	for member, expiry := range s.expiries[name] {
+		expiries[member] = expiry
+	}
+
+	return expiries, nil
+}
+
+func (s *MemoryStore) GetExpiredMembers(now int64) (map[string][]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	expired := make(map[string][]string)
+	for filter := range s.expiries {
+		for member, expiry := range s.expiries[filter] {
+			if expiry <= now {
+				expired[filter] = append(expired[filter], member)
+			}
+		}
+	}
+
+	return expired, nil
+}
+
+func (s *MemoryStore) RemoveExpiredMembers(name string, members []string, now int64) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var removed []string
+	for m := range members {
+		expiry, ok := s.expiries[name][members[m]]
+		if !ok || expiry > now {
+			continue
+		}
+
+		if set, ok := s.filterMembers[name]; ok {
+			set.Remove(members[m])
+		}
+		delete(s.expiries[name], members[m])
+		removed = append(removed, members[m])
+	}
+
+	return removed, nil
+}
+
+func (s *MemoryStore) IsNoSync(member string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.noSync.Contains(member), nil
+}
+
+func (s *MemoryStore) GetNoSync() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.noSync.ToSlice(), nil
+}
+
+func (s *MemoryStore) AddNoSync(members []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.noSync.FromSlice(members)
+	return nil
+}
+
+func (s *MemoryStore) RemoveNoSync(members []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for m := range members {
+		s.noSync.Remove(members[m])
+	}
+	return nil
+}
+
+func (s *MemoryStore) AddJoinRequest(request *JoinRequest) error {
+	return s.addRoleRequest(s.joinRequests, request, ErrJoinRequestExists)
+}
+
+func (s *MemoryStore) GetJoinRequests(role string) ([]JoinRequest, error) {
+	return s.getRoleRequests(s.joinRequests, role)
+}
+
+func (s *MemoryStore) RemoveJoinRequest(role, user string) error {
+	return s.removeRoleRequest(s.joinRequests, role, user, ErrJoinRequestNotFound)
+}
+
+func (s *MemoryStore) AddToWaitlist(entry *JoinRequest) error {
+	return s.addRoleRequest(s.waitlists, entry, ErrWaitlisted)
+}
+
+func (s *MemoryStore) GetWaitlist(role string) ([]JoinRequest, error) {
+	return s.getRoleRequests(s.waitlists, role)
+}
+
+func (s *MemoryStore) RemoveFromWaitlist(role, user string) error {
+	return s.removeRoleRequest(s.waitlists, role, user, ErrNotWaitlisted)
+}
+
+func (s *MemoryStore) addRoleRequest(requests map[string]map[string]JoinRequest, request *JoinRequest, exists error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := requests[request.Role][request.User]; ok {
+		return exists
+	}
+
+	if requests[request.Role] == nil {
+		requests[request.Role] = make(map[string]JoinRequest)
+	}
+	requests[request.Role][request.User] = *request
+	return nil
+}
+
+func (s *MemoryStore) getRoleRequests(requests map[string]map[string]JoinRequest, role string) ([]JoinRequest, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var list []JoinRequest
+	for r := range requests {
+		if role != "" && r != role {
+			continue
+		}
+
+		for _, request := range requests[r] {
+			list = append(list, request)
+		}
+	}
+
+	sortJoinRequests(list)
+	return list, nil
+}
+
+func (s *MemoryStore) removeRoleRequest(requests map[string]map[string]JoinRequest, role, user string, notFound error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := requests[role][user]; !ok {
+		return notFound
+	}
+
+	delete(requests[role], user)
+	return nil
+}
+
+func (s *MemoryStore) AppendAudit(entry *AuditEntry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.auditId++
+	entry.Id = s.auditId
+	s.audit = append(s.audit, *entry)
+
+	if s.auditRetention > 0 && len(s.audit) > s.auditRetention {
+		s.audit = append([]AuditEntry(nil), s.audit[len(s.audit)-s.auditRetention:]...)
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetAudit(query AuditQuery) ([]AuditEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var entries []AuditEntry
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(entries) >= query.Limit {
+			break
+		}
+
+		if query.matches(&s.audit[i]) {
+			entries = append(entries, s.audit[i])
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,292 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestStore is a MemoryStore with one filter, "members", which has a
+// member, one empty filter, "empty", and one role, "existing".
+func newTestStore(t *testing.T) *MemoryStore {
+	s := NewMemoryStore()
+
+	if err := s.CreateFilter("members", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.AddFilterMembers("members", []string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CreateFilter("empty", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CreateRole("existing", map[string]string{"Name": "Existing"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// checkErr fails the test if err isn't want, or for a missing filter, a
+// *MissingFilterError naming it.
+func checkErr(t *testing.T, err, want error, missing string) {
+	t.Helper()
+
+	if missing != "" {
+		var missingFilter *MissingFilterError
+		if !errors.As(err, &missingFilter) || missingFilter.Name != missing {
+			t.Fatalf("got %v, want missing filter %s", err, missing)
+		}
+		return
+	}
+
+	if err != want {
+		t.Fatalf("got %v, want %v", err, want)
+	}
+}
+
+func TestMemoryStoreCreateRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		filters []string
+		err     error
+		missing string
+	}{
+		{name: "new role", role: "new", filters: []string{"members", "empty"}},
+		{name: "no filters", role: "new"},
+		{name: "existing role", role: "existing", err: ErrRoleExists},
+		{name: "missing filter", role: "new", filters: []string{"members", "nope"}, missing: "nope"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestStore(t)
+
+			err := s.CreateRole(test.role, map[string]string{"Name": "New"}, test.filters)
+			checkErr(t, err, test.err, test.missing)
+
+			role, _ := s.GetRole(test.role)
+			switch {
+			case err == nil && role["Name"] != "New":
+				t.Errorf("role not created: %v", role)
+			case err != nil && role["Name"] == "New":
+				t.Errorf("role written despite %v", err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreUpdateRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		filters []string
+		err     error
+		missing string
+	}{
+		{name: "existing role", role: "existing", filters: []string{"members"}},
+		{name: "missing role", role: "nope", err: ErrRoleNotFound},
+		{name: "missing filter", role: "existing", filters: []string{"nope"}, missing: "nope"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestStore(t)
+
+			err := s.UpdateRole(test.role, map[string]string{"Color": "1"}, test.filters)
+			checkErr(t, err, test.err, test.missing)
+
+			role, _ := s.GetRole(test.role)
+			switch {
+			case err == nil && (role["Color"] != "1" || role["Name"] != "Existing"):
+				t.Errorf("role not merged: %v", role)
+			case err != nil && role["Color"] == "1":
+				t.Errorf("role written despite %v", err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRemoveRole(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		err  error
+	}{
+		{name: "existing role", role: "existing"},
+		{name: "missing role", role: "nope", err: ErrRoleNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestStore(t)
+
+			checkErr(t, s.RemoveRole(test.role), test.err, "")
+
+			if exists, _ := s.RoleExists(test.role); exists {
+				t.Errorf("role %s still exists", test.role)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		create string
+		remove string
+		err    error
+	}{
+		{name: "create new filter", create: "new"},
+		{name: "create existing filter", create: "members", err: ErrFilterExists},
+		{name: "remove empty filter", remove: "empty"},
+		{name: "remove filter with members", remove: "members", err: ErrFilterNotEmpty},
+		{name: "remove missing filter", remove: "nope", err: ErrFilterNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestStore(t)
+
+			if test.create != "" {
+				checkErr(t, s.CreateFilter(test.create, ""), test.err, "")
+				return
+			}
+
+			checkErr(t, s.RemoveFilter(test.remove), test.err, "")
+
+			exists, _ := s.FilterExists(test.remove)
+			if exists != (test.err == ErrFilterNotEmpty) {
+				t.Errorf("filter %s exists: %v", test.remove, exists)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRoleRequests(t *testing.T) {
+	s := newTestStore(t)
+	first := &JoinRequest{Role: "existing", User: "1", Time: 2}
+	second := &JoinRequest{Role: "existing", User: "2", Time: 1}
+
+	steps := []struct {
+		name string
+		do   func() error
+		err  error
+	}{
+		{name: "join", do: func() error { return s.AddJoinRequest(first) }},
+		{name: "join again", do: func() error { return s.AddJoinRequest(first) }, err: ErrJoinRequestExists},
+		{name: "waitlist", do: func() error { return s.AddToWaitlist(first) }},
+		{name: "waitlist again", do: func() error { return s.AddToWaitlist(first) }, err: ErrWaitlisted},
+		{name: "waitlist second", do: func() error { return s.AddToWaitlist(second) }},
+		{name: "remove join", do: func() error { return s.RemoveJoinRequest("existing", "1") }},
+		{name: "remove join again", do: func() error { return s.RemoveJoinRequest("existing", "1") }, err: ErrJoinRequestNotFound},
+		{name: "leave waitlist", do: func() error { return s.RemoveFromWaitlist("existing", "1") }},
+		{name: "leave waitlist again", do: func() error { return s.RemoveFromWaitlist("existing", "1") }, err: ErrNotWaitlisted},
+	}
+
+	for _, step := range steps {
+		checkErr(t, step.do(), step.err, "")
+	}
+
+	waiting, _ := s.GetWaitlist("existing")
+	if len(waiting) != 1 || waiting[0].User != "2" {
+		t.Errorf("waitlist is %v, want just user 2", waiting)
+	}
+
+	if err := s.DeleteRole("existing"); err != nil {
+		t.Fatal(err)
+	}
+
+	if waiting, _ = s.GetWaitlist("existing"); len(waiting) != 0 {
+		t.Errorf("waitlist outlived its role: %v", waiting)
+	}
+}
+
+func TestMemoryStoreAudit(t *testing.T) {
+	s := NewMemoryStore()
+	s.auditRetention = 3
+
+	for i := 1; i <= 5; i++ {
+		actor := "a"
+		if i%2 == 0 {
+			actor = "b"
+		}
+
+		if err := s.AppendAudit(&AuditEntry{Time: int64(i), Actor: actor}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query AuditQuery
+		ids   []int64
+	}{
+		{name: "everything kept", ids: []int64{5, 4, 3}},
+		{name: "limit", query: AuditQuery{Limit: 2}, ids: []int64{5, 4}},
+		{name: "actor", query: AuditQuery{Actor: "a"}, ids: []int64{5, 3}},
+		{name: "time range", query: AuditQuery{From: 4, To: 4}, ids: []int64{4}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := s.GetAudit(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []int64
+			for _, e := range entries {
+				ids = append(ids, e.Id)
+			}
+
+			if len(ids) != len(test.ids) {
+				t.Fatalf("got %v, want %v", ids, test.ids)
+			}
+			for i := range ids {
+				if ids[i] != test.ids[i] {
+					t.Fatalf("got %v, want %v", ids, test.ids)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRemoveExpiredMembers(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.AddFilterMembers("members", []string{"2", "3", "4"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 is permanent, 2 expired, 3 was re-added for longer and 4 for good
+	if err := s.SetMemberExpiries("members", map[string]int64{"2": 10, "3": 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := s.RemoveExpiredMembers("members", []string{"1", "2", "3", "4"}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 1 || removed[0] != "2" {
+		t.Errorf("removed %v, want just 2", removed)
+	}
+
+	members, _ := s.GetFilterMembers("members")
+	if len(members) != 3 || validMember(members, "2") {
+		t.Errorf("members are %v, want 1, 3 and 4", members)
+	}
+}
+
+func validMember(members []string, member string) bool {
+	for _, m := range members {
+		if m == member {
+			return true
+		}
+	}
+	return false
+}
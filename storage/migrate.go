@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migrator is implemented by stores that need to bring their schema up to
+// date before they're used. Migrate returns a description of each step it
+// applied so the caller can log it.
+type Migrator interface {
+	Migrate() ([]string, error)
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type sqlMigration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// loadSQLMigrations reads the NNN_name.up.sql / NNN_name.down.sql pairs in dir
+// and returns them ordered by version.
+func loadSQLMigrations(dir string) ([]*sqlMigration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*sqlMigration)
+	for _, f := range files {
+		match := migrationFile.FindStringSubmatch(f.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &sqlMigration{version: version, name: match[2]}
+			byVersion[version] = m
+		} else if m.name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, m.name, match[2])
+		}
+
+		path := filepath.Join(dir, f.Name())
+		if match[3] == "up" {
+			m.up = path
+		} else {
+			m.down = path
+		}
+	}
+
+	var migrations []*sqlMigration
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", m.version, m.name)
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	return migrations, nil
+}
+
+// splitStatements breaks a migration file into individual statements, since
+// the MySQL driver won't run more than one per Exec.
+func splitStatements(contents string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(contents, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
+
+func runSQLFile(db *sql.DB, path string) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range splitStatements(string(contents)) {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("%s: %s", filepath.Base(path), err)
+		}
+	}
+
+	return nil
+}
+
+// markDirty records that a step is about to run. MySQL can't roll back DDL,
+// so if the step fails partway through the row stays behind to say the schema
+// is somewhere between two versions.
+func markDirty(db *sql.DB, m *sqlMigration, direction string) error {
+	_, err := db.Exec("INSERT INTO schema_migrations_dirty (version, name, direction, started) VALUES (?, ?, ?, NOW())",
+		m.version, m.name, direction)
+	return err
+}
+
+// finishStep records a step in schema_migrations, using record, and clears the
+// dirty row in one transaction.
+func finishStep(db *sql.DB, record string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations_dirty"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MigrateSQL brings the database schema to the target version using the
+// migration files in dir, applying up files in order or, if down is set, down
+// files in reverse order as needed. Without down a schema newer than target is
+// an error, since down files drop tables and columns. A target of 0 means the
+// newest version available. The applied versions are recorded in the
+// schema_migrations table. It returns a description of every step it ran.
+//
+// Each step is marked in schema_migrations_dirty while it runs. If one fails
+// the mark stays, and MigrateSQL refuses to run again until someone has
+// finished or undone the step by hand and deleted the mark.
+func MigrateSQL(db *sql.DB, dir string, target int, down bool) ([]string, error) {
+	var applied []string
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INT PRIMARY KEY NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  applied TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations_dirty (
+  version INT PRIMARY KEY NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  direction VARCHAR(4) NOT NULL,
+  started TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirty struct {
+		version   int
+		name      string
+		direction string
+	}
+	err = db.QueryRow("SELECT version, name, direction FROM schema_migrations_dirty").Scan(&dirty.version, &dirty.name, &dirty.direction)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("schema migration %s %03d_%s failed partway through; finish or undo it by hand, then delete its row from schema_migrations_dirty",
+			dirty.direction, dirty.version, dirty.name)
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+
+	migrations, err := loadSQLMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations found in %s", dir)
+	}
+
+	if target == 0 {
+		target = migrations[len(migrations)-1].version
+	}
+
+	var current int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return nil, err
+	}
+
+	if current > migrations[len(migrations)-1].version {
+		return nil, fmt.Errorf("database schema version %d is newer than the newest migration (%d)",
+			current, migrations[len(migrations)-1].version)
+	}
+
+	if current > target && !down {
+		return nil, fmt.Errorf("database schema version %d is newer than the target version %d; roll it back with role-migrate -schema %d",
+			current, target, target)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current || m.version > target {
+			continue
+		}
+
+		if err := markDirty(db, m, "up"); err != nil {
+			return applied, err
+		}
+
+		if err := runSQLFile(db, m.up); err != nil {
+			return applied, err
+		}
+
+		err = finishStep(db, "INSERT INTO schema_migrations (version, name, applied) VALUES (?, ?, NOW())", m.version, m.name)
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, fmt.Sprintf("up %03d_%s", m.version, m.name))
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version > current || m.version <= target {
+			continue
+		}
+
+		if m.down == "" {
+			return applied, fmt.Errorf("migration %d (%s) has no down file", m.version, m.name)
+		}
+
+		if err := markDirty(db, m, "down"); err != nil {
+			return applied, err
+		}
+
+		if err := runSQLFile(db, m.down); err != nil {
+			return applied, err
+		}
+
+		err = finishStep(db, "DELETE FROM schema_migrations WHERE version = ?", m.version)
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, fmt.Sprintf("down %03d_%s", m.version, m.name))
+	}
+
+	return applied, nil
+}
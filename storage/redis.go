@@ -0,0 +1,649 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	redis "github.com/chremoas/services-common/redis"
+	goredis "github.com/go-redis/redis"
+)
+
+// RedisStore is the original storage layout:
+//
+//	role:<ShortName>            hash of role fields
+//	filter_description:<name>  filter description
+//	filter_members:<name>      set of member IDs
+//	filter_expiry:<name>       sorted set of member IDs scored by expiry time
+//	members:no_sync            set of member IDs to skip when syncing
+//	index:roles                set of every role ShortName
+//	index:filters              set of every filter name
+//	audit:log                  sorted set of JSON audit entries scored by Id
+//	audit:actor:<actor>        the same, for one actor
+//	audit:target:<target>      the same, for one target
+//	audit:sequence             last audit entry Id handed out
+//
+// All keys live under the client's prefix. The index sets are updated in the
+// same MULTI as the keys they track, so listing roles and filters never needs
+// a KEYS scan of the shared instance.
+type RedisStore struct {
+	redis          *redis.Client
+	auditRetention int
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{redis: client}
+}
+
+func (s *RedisStore) roleKey(name string) string {
+	return s.redis.KeyName(fmt.Sprintf("role:%s", name))
+}
+
+func (s *RedisStore) filterKey(name string) string {
+	return s.redis.KeyName(fmt.Sprintf("filter_description:%s", name))
+}
+
+func (s *RedisStore) membersKey(name string) string {
+	return s.redis.KeyName(fmt.Sprintf("filter_members:%s", name))
+}
+
+func (s *RedisStore) expiryKey(name string) string {
+	return s.redis.KeyName(fmt.Sprintf("filter_expiry:%s", name))
+}
+
+func (s *RedisStore) rolesIndex() string {
+	return s.redis.KeyName("index:roles")
+}
+
+func (s *RedisStore) filtersIndex() string {
+	return s.redis.KeyName("index:filters")
+}
+
+func (s *RedisStore) joinRequestsKey(role string) string {
+	return s.redis.KeyName(fmt.Sprintf("join_requests:%s", role))
+}
+
+func (s *RedisStore) waitlistKey(role string) string {
+	return s.redis.KeyName(fmt.Sprintf("waitlist:%s", role))
+}
+
+func (s *RedisStore) auditKey() string {
+	return s.redis.KeyName("audit:log")
+}
+
+func (s *RedisStore) auditActorKey(actor string) string {
+	return s.redis.KeyName(fmt.Sprintf("audit:actor:%s", actor))
+}
+
+func (s *RedisStore) auditTargetKey(target string) string {
+	return s.redis.KeyName(fmt.Sprintf("audit:target:%s", target))
+}
+
+func (s *RedisStore) auditSequenceKey() string {
+	return s.redis.KeyName("audit:sequence")
+}
+
+func (s *RedisStore) GetRoles() ([]string, error) {
+	return s.redis.Client.SMembers(s.rolesIndex()).Result()
+}
+
+func (s *RedisStore) RoleExists(name string) (bool, error) {
+	exists, err := s.redis.Client.Exists(s.roleKey(name)).Result()
+	return exists == 1, err
+}
+
+func (s *RedisStore) GetRole(name string) (map[string]string, error) {
+	return s.redis.Client.HGetAll(s.roleKey(name)).Result()
+}
+
+func (s *RedisStore) SetRole(name string, fields map[string]string) error {
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		s.setRole(pipe, name, fields)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) setRole(pipe goredis.Pipeliner, name string, fields map[string]string) {
+	values := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		values[k] = v
+	}
+
+	pipe.HMSet(s.roleKey(name), values)
+	pipe.SAdd(s.rolesIndex(), name)
+}
+
+func (s *RedisStore) DeleteRole(name string) error {
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		s.deleteRole(pipe, name)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) deleteRole(pipe goredis.Pipeliner, name string) {
+	pipe.Del(s.roleKey(name))
+	pipe.Del(s.joinRequestsKey(name))
+	pipe.Del(s.waitlistKey(name))
+	pipe.SRem(s.rolesIndex(), name)
+}
+
+// The checked mutations below WATCH every key they look at, so if any of them
+// changes before the MULTI runs the whole thing is dropped and ErrConflict is
+// returned instead of writing over someone else's change.
+
+func (s *RedisStore) CreateRole(name string, fields map[string]string, filters []string) error {
+	return s.checkedSetRole(name, fields, filters, false)
+}
+
+func (s *RedisStore) UpdateRole(name string, fields map[string]string, filters []string) error {
+	return s.checkedSetRole(name, fields, filters, true)
+}
+
+// checkedSetRole writes the role if it exists (or doesn't, when creating) and
+// every filter in filters exists.
+func (s *RedisStore) checkedSetRole(name string, fields map[string]string, filters []string, update bool) error {
+	keys := []string{s.roleKey(name)}
+	for f := range filters {
+		keys = append(keys, s.filterKey(filters[f]))
+	}
+
+	return watchErr(s.redis.Client.Watch(func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(s.roleKey(name)).Result()
+		if err != nil {
+			return err
+		}
+
+		if update && exists == 0 {
+			return ErrRoleNotFound
+		}
+
+		if !update && exists == 1 {
+			return ErrRoleExists
+		}
+
+		for f := range filters {
+			exists, err = tx.Exists(s.filterKey(filters[f])).Result()
+			if err != nil {
+				return err
+			}
+
+			if exists == 0 {
+				return &MissingFilterError{Name: filters[f]}
+			}
+		}
+
+		_, err = tx.TxPipelined(func(pipe goredis.Pipeliner) error {
+			s.setRole(pipe, name, fields)
+			return nil
+		})
+		return err
+	}, keys...))
+}
+
+func (s *RedisStore) RemoveRole(name string) error {
+	return watchErr(s.redis.Client.Watch(func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(s.roleKey(name)).Result()
+		if err != nil {
+			return err
+		}
+
+		if exists == 0 {
+			return ErrRoleNotFound
+		}
+
+		_, err = tx.TxPipelined(func(pipe goredis.Pipeliner) error {
+			s.deleteRole(pipe, name)
+			return nil
+		})
+		return err
+	}, s.roleKey(name)))
+}
+
+func watchErr(err error) error {
+	if err == goredis.TxFailedErr {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *RedisStore) GetFilters() (map[string]string, error) {
+	var filterList = make(map[string]string)
+	filters, err := s.redis.Client.SMembers(s.filtersIndex()).Result()
+
+	if err != nil || len(filters) == 0 {
+		return filterList, err
+	}
+
+	keys := make([]string, len(filters))
+	for filter := range filters {
+		keys[filter] = s.filterKey(filters[filter])
+	}
+
+	descriptions, err := s.redis.Client.MGet(keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for filter := range filters {
+		// Skip anything that's in the index without a description key
+		if description, ok := descriptions[filter].(string); ok {
+			filterList[filters[filter]] = description
+		}
+	}
+
+	return filterList, nil
+}
+
+func (s *RedisStore) FilterExists(name string) (bool, error) {
+	exists, err := s.redis.Client.Exists(s.filterKey(name)).Result()
+	return exists == 1, err
+}
+
+func (s *RedisStore) SetFilter(name, description string) error {
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		s.setFilter(pipe, name, description)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) setFilter(pipe goredis.Pipeliner, name, description string) {
+	pipe.Set(s.filterKey(name), description, 0)
+	pipe.SAdd(s.filtersIndex(), name)
+}
+
+func (s *RedisStore) DeleteFilter(name string) error {
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		s.deleteFilter(pipe, name)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) deleteFilter(pipe goredis.Pipeliner, name string) {
+	pipe.Del(s.filterKey(name), s.expiryKey(name))
+	pipe.SRem(s.filtersIndex(), name)
+}
+
+func (s *RedisStore) CreateFilter(name, description string) error {
+	return watchErr(s.redis.Client.Watch(func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(s.filterKey(name)).Result()
+		if err != nil {
+			return err
+		}
+
+		if exists == 1 {
+			return ErrFilterExists
+		}
+
+		_, err = tx.TxPipelined(func(pipe goredis.Pipeliner) error {
+			s.setFilter(pipe, name, description)
+			return nil
+		})
+		return err
+	}, s.filterKey(name)))
+}
+
+func (s *RedisStore) RemoveFilter(name string) error {
+	return watchErr(s.redis.Client.Watch(func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(s.filterKey(name)).Result()
+		if err != nil {
+			return err
+		}
+
+		if exists == 0 {
+			return ErrFilterNotFound
+		}
+
+		members, err := tx.SCard(s.membersKey(name)).Result()
+		if err != nil {
+			return err
+		}
+
+		if members > 0 {
+			return ErrFilterNotEmpty
+		}
+
+		_, err = tx.TxPipelined(func(pipe goredis.Pipeliner) error {
+			s.deleteFilter(pipe, name)
+			return nil
+		})
+		return err
+	}, s.filterKey(name), s.membersKey(name)))
+}
+
+func (s *RedisStore) GetFilterMembers(name string) ([]string, error) {
+	return s.redis.Client.SMembers(s.membersKey(name)).Result()
+}
+
+func (s *RedisStore) AddFilterMembers(name string, members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	return s.redis.Client.SAdd(s.membersKey(name), stringsToInterfaces(members)...).Err()
+}
+
+func (s *RedisStore) RemoveFilterMembers(name string, members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		pipe.SRem(s.membersKey(name), stringsToInterfaces(members)...)
+		pipe.ZRem(s.expiryKey(name), stringsToInterfaces(members)...)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) SetMemberExpiries(name string, expiries map[string]int64) error {
+	if len(expiries) == 0 {
+		return nil
+	}
+
+	_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		for member, expiry := range expiries {
+			if expiry == 0 {
+				pipe.ZRem(s.expiryKey(name), member)
+			} else {
+				pipe.ZAdd(s.expiryKey(name), goredis.Z{Score: float64(expiry), Member: member})
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) GetMemberExpiries(name string) (map[string]int64, error) {
+	members, err := s.redis.Client.ZRangeWithScores(s.expiryKey(name), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	expiries := make(map[string]int64, len(members))
+	for m := range members {
+		expiries[members[m].Member.(string)] = int64(members[m].Score)
+	}
+
+	return expiries, nil
+}
+
+func (s *RedisStore) RemoveExpiredMembers(name string, members []string, now int64) ([]string, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	var removed []string
+	err := watchErr(s.redis.Client.Watch(func(tx *goredis.Tx) error {
+		removed = nil
+		for m := range members {
+			expiry, err := tx.ZScore(s.expiryKey(name), members[m]).Result()
+			if err == goredis.Nil {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if int64(expiry) <= now {
+				removed = append(removed, members[m])
+			}
+		}
+
+		if len(removed) == 0 {
+			return nil
+		}
+
+		_, err := tx.TxPipelined(func(pipe goredis.Pipeliner) error {
+			pipe.SRem(s.membersKey(name), stringsToInterfaces(removed)...)
+			pipe.ZRem(s.expiryKey(name), stringsToInterfaces(removed)...)
+			return nil
+		})
+		return err
+	}, s.expiryKey(name), s.membersKey(name)))
+
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (s *RedisStore) GetExpiredMembers(now int64) (map[string][]string, error) {
+	filters, err := s.redis.Client.SMembers(s.filtersIndex()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make(map[string][]string)
+	for filter := range filters {
+		members, err := s.redis.Client.ZRangeByScore(s.expiryKey(filters[filter]), goredis.ZRangeBy{
+			Min: "-inf",
+			Max: strconv.FormatInt(now, 10),
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(members) > 0 {
+			expired[filters[filter]] = members
+		}
+	}
+
+	return expired, nil
+}
+
+func (s *RedisStore) IsNoSync(member string) (bool, error) {
+	return s.redis.Client.SIsMember(s.redis.KeyName("members:no_sync"), member).Result()
+}
+
+func (s *RedisStore) GetNoSync() ([]string, error) {
+	return s.redis.Client.SMembers(s.redis.KeyName("members:no_sync")).Result()
+}
+
+func (s *RedisStore) AddNoSync(members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	return s.redis.Client.SAdd(s.redis.KeyName("members:no_sync"), stringsToInterfaces(members)...).Err()
+}
+
+func (s *RedisStore) RemoveNoSync(members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	return s.redis.Client.SRem(s.redis.KeyName("members:no_sync"), stringsToInterfaces(members)...).Err()
+}
+
+func stringsToInterfaces(s []string) []interface{} {
+	out := make([]interface{}, len(s))
+	for i := range s {
+		out[i] = s[i]
+	}
+	return out
+}
+
+// Join requests and waitlists are a hash per role of user to the request as
+// JSON.
+
+func (s *RedisStore) AddJoinRequest(request *JoinRequest) error {
+	return s.addRoleRequest(s.joinRequestsKey, request, ErrJoinRequestExists)
+}
+
+func (s *RedisStore) GetJoinRequests(role string) ([]JoinRequest, error) {
+	return s.getRoleRequests(s.joinRequestsKey, role)
+}
+
+func (s *RedisStore) RemoveJoinRequest(role, user string) error {
+	return s.removeRoleRequest(s.joinRequestsKey, role, user, ErrJoinRequestNotFound)
+}
+
+func (s *RedisStore) AddToWaitlist(entry *JoinRequest) error {
+	return s.addRoleRequest(s.waitlistKey, entry, ErrWaitlisted)
+}
+
+func (s *RedisStore) GetWaitlist(role string) ([]JoinRequest, error) {
+	return s.getRoleRequests(s.waitlistKey, role)
+}
+
+func (s *RedisStore) RemoveFromWaitlist(role, user string) error {
+	return s.removeRoleRequest(s.waitlistKey, role, user, ErrNotWaitlisted)
+}
+
+func (s *RedisStore) addRoleRequest(key func(string) string, request *JoinRequest, exists error) error {
+	value, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	added, err := s.redis.Client.HSetNX(key(request.Role), request.User, value).Result()
+	if err != nil {
+		return err
+	}
+
+	if !added {
+		return exists
+	}
+	return nil
+}
+
+func (s *RedisStore) getRoleRequests(key func(string) string, role string) ([]JoinRequest, error) {
+	roles := []string{role}
+	if role == "" {
+		var err error
+		roles, err = s.GetRoles()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var requests []JoinRequest
+	for r := range roles {
+		values, err := s.redis.Client.HVals(key(roles[r])).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			var request JoinRequest
+			if err := json.Unmarshal([]byte(value), &request); err != nil {
+				return nil, err
+			}
+			requests = append(requests, request)
+		}
+	}
+
+	sortJoinRequests(requests)
+	return requests, nil
+}
+
+func (s *RedisStore) removeRoleRequest(key func(string) string, role, user string, notFound error) error {
+	removed, err := s.redis.Client.HDel(key(role), user).Result()
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// auditKeys is the log and the indexes entry goes in.
+func (s *RedisStore) auditKeys(entry *AuditEntry) []string {
+	return []string{s.auditKey(), s.auditActorKey(entry.Actor), s.auditTargetKey(entry.Target)}
+}
+
+// AppendAudit stores the entry as JSON so each one is a distinct member of the
+// sorted set, Id included.
+func (s *RedisStore) AppendAudit(entry *AuditEntry) error {
+	id, err := s.redis.Client.Incr(s.auditSequenceKey()).Result()
+	if err != nil {
+		return err
+	}
+	entry.Id = id
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Trimming the keys this entry went in keeps every index that's still
+	// written to within the retention; GetAudit skips anything older left in
+	// the rest.
+	_, err = s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+		for _, key := range s.auditKeys(entry) {
+			pipe.ZAdd(key, goredis.Z{Score: float64(id), Member: value})
+			if s.auditRetention > 0 {
+				pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(id-int64(s.auditRetention), 10))
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// auditPage is how many entries GetAudit reads at a time.
+const auditPage = 100
+
+// GetAudit reads the narrowest index for query newest first, a page at a
+// time, until it has Limit entries. Entries are in the order they were
+// written, so it stops at the first one older than From.
+func (s *RedisStore) GetAudit(query AuditQuery) ([]AuditEntry, error) {
+	key := s.auditKey()
+	switch {
+	case query.Target != "":
+		key = s.auditTargetKey(query.Target)
+	case query.Actor != "":
+		key = s.auditActorKey(query.Actor)
+	}
+
+	min := "-inf"
+	if s.auditRetention > 0 {
+		last, err := s.redis.Client.Get(s.auditSequenceKey()).Int64()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		min = fmt.Sprintf("(%d", last-int64(s.auditRetention))
+	}
+
+	var entries []AuditEntry
+	for offset := int64(0); ; offset += auditPage {
+		values, err := s.redis.Client.ZRevRangeByScore(key, goredis.ZRangeBy{
+			Min:    min,
+			Max:    "+inf",
+			Offset: offset,
+			Count:  auditPage,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			var entry AuditEntry
+			if err := json.Unmarshal([]byte(value), &entry); err != nil {
+				return nil, err
+			}
+
+			if entry.Time < query.From {
+				return entries, nil
+			}
+
+			if query.matches(&entry) {
+				entries = append(entries, entry)
+				if query.Limit > 0 && len(entries) >= query.Limit {
+					return entries, nil
+				}
+			}
+		}
+
+		if len(values) < auditPage {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	redis "github.com/chremoas/services-common/redis"
+	goredis "github.com/go-redis/redis"
+)
+
+// redisMigrations is every change ever made to the Redis layout, in the order
+// it has to be applied. The number of migrations applied so far is kept in
+// schema:version, so entries must only ever be appended to this list.
+var redisMigrations = []struct {
+	name    string
+	migrate func(s *RedisStore) error
+}{
+	{"build role and filter index sets", (*RedisStore).backfillIndexes},
+	{"default role Sync to 1", (*RedisStore).defaultSync},
+	{"score the audit log by Id and index it by actor and target", (*RedisStore).indexAudit},
+}
+
+func (s *RedisStore) versionKey() string {
+	return s.redis.KeyName("schema:version")
+}
+
+// Migrate runs every Redis migration newer than the stored schema version.
+// Each one records its version as soon as it finishes, so a failure part way
+// through picks up from the failed migration next time.
+func (s *RedisStore) Migrate() ([]string, error) {
+	var applied []string
+
+	version, err := s.redis.Client.Get(s.versionKey()).Int()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	if version > len(redisMigrations) {
+		return nil, fmt.Errorf("redis schema version %d is newer than the newest migration (%d)",
+			version, len(redisMigrations))
+	}
+
+	for v := version; v < len(redisMigrations); v++ {
+		m := redisMigrations[v]
+		if err := m.migrate(s); err != nil {
+			return applied, fmt.Errorf("redis migration %d (%s): %s", v+1, m.name, err)
+		}
+
+		if err := s.redis.Client.Set(s.versionKey(), v+1, 0).Err(); err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, fmt.Sprintf("redis %03d %s", v+1, m.name))
+	}
+
+	return applied, nil
+}
+
+// backfillIndexes builds the role and filter index sets from the keys that
+// were written before the sets existed. SCAN is used rather than KEYS so the
+// shared instance isn't blocked while it runs.
+func (s *RedisStore) backfillIndexes() error {
+	if err := s.backfillIndex(s.rolesIndex(), s.roleKey("")); err != nil {
+		return err
+	}
+
+	return s.backfillIndex(s.filtersIndex(), s.filterKey(""))
+}
+
+func (s *RedisStore) backfillIndex(index, keyPrefix string) error {
+	iter := s.redis.Client.Scan(0, keyPrefix+"*", 1000).Iterator()
+	for iter.Next() {
+		name := strings.TrimPrefix(iter.Val(), keyPrefix)
+		if err := s.redis.Client.SAdd(index, name).Err(); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// defaultSync sets Sync on roles created before it existed, which were all
+// being synced.
+func (s *RedisStore) defaultSync() error {
+	roles, err := s.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	for role := range roles {
+		sync, err := s.redis.Client.HGet(s.roleKey(roles[role]), "Sync").Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		if sync == "" {
+			if err := s.redis.Client.HSet(s.roleKey(roles[role]), "Sync", "1").Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexAudit rescores the audit log, which was scored by time, by Id and
+// builds the actor and target indexes. ZSCAN is safe while the scores change
+// under it; anything it returns twice is just written twice.
+func (s *RedisStore) indexAudit() error {
+	iter := s.redis.Client.ZScan(s.auditKey(), 0, "", 1000).Iterator()
+	for iter.Next() {
+		value := iter.Val()
+
+		// ZSCAN returns each member followed by its score
+		if !iter.Next() {
+			break
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return err
+		}
+
+		_, err := s.redis.Client.TxPipelined(func(pipe goredis.Pipeliner) error {
+			for _, key := range s.auditKeys(&entry) {
+				pipe.ZAdd(key, goredis.Z{Score: float64(entry.Id), Member: value})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"fmt"
+
+	redis "github.com/chremoas/services-common/redis"
+	"github.com/spf13/viper"
+)
+
+// RoleStore persists Chremoas roles. Roles are passed around as flat field
+// maps keyed by the Role field names (Name, Color, FilterA, ...), which is the
+// shape they've always had in the Redis role hash.
+type RoleStore interface {
+	// GetRoles returns the ShortName of every role.
+	GetRoles() ([]string, error)
+	RoleExists(name string) (bool, error)
+	// GetRole returns an empty map if the role doesn't exist.
+	GetRole(name string) (map[string]string, error)
+	// SetRole creates the role or merges fields into an existing one.
+	SetRole(name string, fields map[string]string) error
+	DeleteRole(name string) error
+
+	// CreateRole stores a new role, checking atomically with the write that
+	// the role doesn't exist yet (ErrRoleExists) and that every filter in
+	// filters does (*MissingFilterError).
+	CreateRole(name string, fields map[string]string, filters []string) error
+	// UpdateRole merges fields into an existing role or returns
+	// ErrRoleNotFound. Like CreateRole, every filter in filters has to exist.
+	UpdateRole(name string, fields map[string]string, filters []string) error
+	// RemoveRole deletes an existing role or returns ErrRoleNotFound.
+	RemoveRole(name string) error
+}
+
+// FilterStore persists filters, their members and the list of users that
+// should never be touched by a sync.
+type FilterStore interface {
+	// GetFilters returns a map of filter name to description.
+	GetFilters() (map[string]string, error)
+	FilterExists(name string) (bool, error)
+	SetFilter(name, description string) error
+	DeleteFilter(name string) error
+
+	// CreateFilter stores a new filter or returns ErrFilterExists.
+	CreateFilter(name, description string) error
+	// RemoveFilter deletes a filter that has no members, returning
+	// ErrFilterNotFound or ErrFilterNotEmpty otherwise.
+	RemoveFilter(name string) error
+
+	GetFilterMembers(name string) ([]string, error)
+	AddFilterMembers(name string, members []string) error
+	RemoveFilterMembers(name string, members []string) error
+
+	// SetMemberExpiries records the unix time each member should be taken out
+	// of the filter. An expiry of 0 makes the membership permanent again.
+	SetMemberExpiries(name string, expiries map[string]int64) error
+	// GetMemberExpiries returns the expiry of every member that has one.
+	GetMemberExpiries(name string) (map[string]int64, error)
+	// GetExpiredMembers returns, by filter, the members whose expiry is at or
+	// before now.
+	GetExpiredMembers(now int64) (map[string][]string, error)
+	// RemoveExpiredMembers takes members out of the filter if their expiry is
+	// still at or before now, checked atomically with the removal, and
+	// returns the ones it took out. Anyone made permanent or given a later
+	// expiry since GetExpiredMembers stays.
+	RemoveExpiredMembers(name string, members []string, now int64) ([]string, error)
+
+	IsNoSync(member string) (bool, error)
+	GetNoSync() ([]string, error)
+	AddNoSync(members []string) error
+	RemoveNoSync(members []string) error
+}
+
+// Store is everything the roles handler needs from a storage backend. Any of
+// the checked mutations (CreateRole, RemoveFilter, ...) may also fail with
+// ErrConflict if a concurrent change got in the way.
+type Store interface {
+	RoleStore
+	FilterStore
+	JoinRequestStore
+	WaitlistStore
+	AuditStore
+}
+
+// defaultAuditRetention is how many audit entries are kept unless
+// roles.auditRetention says otherwise.
+const defaultAuditRetention = 100000
+
+// ConnectionStringer builds the SQL connection string from the database
+// section of the config; services-common's *config.Configuration is one.
+type ConnectionStringer interface {
+	NewConnectionString() (string, error)
+}
+
+// Open returns the named backend: redis (the default), sql or memory. The SQL
+// backend is configured from the database section of the config.
+func Open(backend string, conf ConnectionStringer, redisClient *redis.Client) (Store, error) {
+	retention := defaultAuditRetention
+	if viper.IsSet("roles.auditRetention") {
+		retention = viper.GetInt("roles.auditRetention")
+	}
+
+	switch backend {
+	case "", "redis":
+		store := NewRedisStore(redisClient)
+		store.auditRetention = retention
+		return store, nil
+	case "memory":
+		store := NewMemoryStore()
+		store.auditRetention = retention
+		return store, nil
+	case "sql":
+		connectionString, err := conf.NewConnectionString()
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := OpenSQL(connectionString)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations := viper.GetString("database.migrations")
+		if migrations == "" {
+			migrations = "sql"
+		}
+
+		store := NewSQLStore(db, migrations, viper.GetInt("database.schemaVersion"))
+		store.auditRetention = retention
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}